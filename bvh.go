@@ -0,0 +1,308 @@
+package geo2
+
+import (
+	"math"
+	"sort"
+)
+
+// BVHSplit selects how a BVH divides triangles between nodes
+type BVHSplit int
+
+const (
+	// BVHSplitSAH splits nodes where the surface area heuristic
+	// (perimeter in 2d) estimates queries will be cheapest. It
+	// takes longer to build but produces faster trees
+	BVHSplitSAH BVHSplit = iota
+	// BVHSplitMedian splits nodes at the median triangle
+	// along their longest axis
+	BVHSplitMedian
+)
+
+// bvhLeafSize is the most triangles stored in a single leaf
+const bvhLeafSize = 4
+
+// BVH is a bounding volume hierarchy over the triangles of a
+// TriangleList, for fast point, ray and area queries. Queries return
+// the index of triangles within the list. If the vertices of the
+// triangles move, call Refit to update the hierarchy
+type BVH struct {
+	triangles *TriangleList
+	nodes     []bvhNode
+	// order holds the triangle indices, each leaf
+	// covers a contiguous range of this slice
+	order []int
+}
+
+type bvhNode struct {
+	bounds Rectangle
+	// left and right are the child node indices, or -1 for a leaf
+	left, right  int
+	start, count int
+}
+
+// NewBVH builds a hierarchy over the given triangles. The list
+// is referenced rather than copied, so it must not be resized
+func NewBVH(tris *TriangleList, split BVHSplit) *BVH {
+	bvh := &BVH{triangles: tris, order: make([]int, len(*tris))}
+	bounds := make([]*Rectangle, len(*tris))
+	centers := make([]*Vector, len(*tris))
+	for i, tri := range *tris {
+		bvh.order[i] = i
+		bounds[i] = NewRectangleFromPoints(tri.Points)
+		centers[i] = bounds[i].Center()
+	}
+	if len(*tris) > 0 {
+		bvh.build(0, len(*tris), split, bounds, centers)
+	}
+	return bvh
+}
+
+// build creates the node for the given range of
+// the triangle order, returning its index
+func (bvh *BVH) build(start, end int, split BVHSplit, bounds []*Rectangle, centers []*Vector) int {
+	index := len(bvh.nodes)
+	bvh.nodes = append(bvh.nodes, bvhNode{left: -1, right: -1, start: start, count: end - start})
+	nodeBounds := bounds[bvh.order[start]].Clone()
+	centerBounds := NewRectangleFromPoints([]*Vector{centers[bvh.order[start]]})
+	for _, i := range bvh.order[start+1 : end] {
+		nodeBounds = nodeBounds.Union(bounds[i])
+		centerBounds = centerBounds.Union(NewRectangleFromPoints([]*Vector{centers[i]}))
+	}
+	bvh.nodes[index].bounds = *nodeBounds
+	if end-start <= bvhLeafSize || (0 == centerBounds.Width && 0 == centerBounds.Height) {
+		return index
+	}
+
+	axis := 0
+	if centerBounds.Height > centerBounds.Width {
+		axis = 1
+	}
+	items := bvh.order[start:end]
+	sort.Slice(items, func(i, j int) bool {
+		return kdAxis(centers[items[i]], axis) < kdAxis(centers[items[j]], axis)
+	})
+
+	mid := start + (end-start)/2
+	if BVHSplitSAH == split {
+		mid = start + bvhSAHSplit(items, bounds, nodeBounds)
+		if mid == start {
+			// no split is cheaper than testing every triangle
+			return index
+		}
+	}
+
+	left := bvh.build(start, mid, split, bounds, centers)
+	right := bvh.build(mid, end, split, bounds, centers)
+	bvh.nodes[index].left = left
+	bvh.nodes[index].right = right
+	return index
+}
+
+// bvhSAHSplit returns the position in the sorted items at which to
+// split them according to the surface area heuristic, or zero if
+// keeping them all in a leaf is estimated to be cheaper
+func bvhSAHSplit(items []int, bounds []*Rectangle, parent *Rectangle) int {
+	count := len(items)
+	perimeter := func(r *Rectangle) float64 {
+		return r.Width + r.Height
+	}
+	// sweep from the right to find the cost of each right hand group
+	rightCost := make([]float64, count)
+	acc := bounds[items[count-1]].Clone()
+	for i := count - 1; i > 0; i-- {
+		acc = acc.Union(bounds[items[i]])
+		rightCost[i] = perimeter(acc) * float64(count-i)
+	}
+
+	best := 0
+	bestCost := float64(count)
+	parentPerimeter := perimeter(parent)
+	if 0 == parentPerimeter {
+		return count / 2
+	}
+	acc = bounds[items[0]].Clone()
+	for i := 1; i < count; i++ {
+		cost := 0.125 + (perimeter(acc)*float64(i)+rightCost[i])/parentPerimeter
+		if cost < bestCost {
+			best, bestCost = i, cost
+		}
+		acc = acc.Union(bounds[items[i]])
+	}
+	return best
+}
+
+// Refit updates the bounds of every node after the vertices of the
+// triangles have moved. The structure of the tree is kept, so queries
+// may slow down if the triangles move a long way, in which case the
+// hierarchy should be rebuilt instead
+func (bvh *BVH) Refit() {
+	// children are always created after their parent
+	for i := len(bvh.nodes) - 1; i >= 0; i-- {
+		node := &bvh.nodes[i]
+		if node.left < 0 {
+			var points []*Vector
+			for _, t := range bvh.order[node.start : node.start+node.count] {
+				points = append(points, (*bvh.triangles)[t].Points...)
+			}
+			node.bounds = *NewRectangleFromPoints(points)
+			continue
+		}
+		node.bounds = *bvh.nodes[node.left].bounds.Union(&bvh.nodes[node.right].bounds)
+	}
+}
+
+// TriangleAt returns the index of a triangle containing the
+// given point, or -1 if no triangle contains it
+func (bvh *BVH) TriangleAt(point *Vector) int {
+	result := -1
+	bvh.visit(func(bounds *Rectangle) bool {
+		return bounds.Contains(point)
+	}, func(t int) bool {
+		if (*bvh.triangles)[t].Contains(point) {
+			result = t
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+// QueryRectangle returns the indices of every triangle
+// which overlaps or touches the given rectangle
+func (bvh *BVH) QueryRectangle(rect *Rectangle) []int {
+	area := rect.Clone().Normalize()
+	corners := area.Corners()
+	var result []int
+	bvh.visit(func(bounds *Rectangle) bool {
+		return bounds.Overlaps(area)
+	}, func(t int) bool {
+		if convexOverlap((*bvh.triangles)[t].Points, corners) {
+			result = append(result, t)
+		}
+		return true
+	})
+	sort.Ints(result)
+	return result
+}
+
+// Raycast finds the first triangle hit by the ray starting at origin
+// and travelling in the given direction, returning its index and the
+// distance to the hit, or -1 if nothing is hit. A ray starting inside
+// a triangle hits it at a distance of zero
+func (bvh *BVH) Raycast(origin, direction *Vector) (int, float64) {
+	dir := direction.Clone()
+	if 0 == dir.LengthSqd() || 0 == len(bvh.nodes) {
+		return -1, 0
+	}
+	dir.Normalize()
+
+	best, bestDist := -1, math.Inf(1)
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &bvh.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if near, ok := raySlab(origin, dir, &node.bounds); !ok || near > bestDist {
+			continue
+		}
+		if node.left >= 0 {
+			stack = append(stack, node.left, node.right)
+			continue
+		}
+		for _, t := range bvh.order[node.start : node.start+node.count] {
+			if d, ok := rayTriangle(origin, dir, (*bvh.triangles)[t]); ok && d < bestDist {
+				best, bestDist = t, d
+			}
+		}
+	}
+	if best < 0 {
+		return -1, 0
+	}
+	return best, bestDist
+}
+
+// visit walks the nodes whose bounds pass the given test, calling leaf
+// for each of their triangles until it returns false
+func (bvh *BVH) visit(test func(*Rectangle) bool, leaf func(int) bool) {
+	if 0 == len(bvh.nodes) {
+		return
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &bvh.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !test(&node.bounds) {
+			continue
+		}
+		if node.left >= 0 {
+			stack = append(stack, node.left, node.right)
+			continue
+		}
+		for _, t := range bvh.order[node.start : node.start+node.count] {
+			if !leaf(t) {
+				return
+			}
+		}
+	}
+}
+
+// raySlab returns the distance along the ray (with a unit direction)
+// at which it enters the given rectangle, or false if it misses
+func raySlab(origin, dir *Vector, rect *Rectangle) (float64, bool) {
+	near, far := 0.0, math.Inf(1)
+	for axis := 0; axis < 2; axis++ {
+		o, d := origin.X, dir.X
+		min, max := rect.X, rect.Right()
+		if 1 == axis {
+			o, d = origin.Y, dir.Y
+			min, max = rect.Y, rect.Bottom()
+		}
+		if 0 == d {
+			if o < min || o > max {
+				return 0, false
+			}
+			continue
+		}
+		t0, t1 := (min-o)/d, (max-o)/d
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		near = math.Max(near, t0)
+		far = math.Min(far, t1)
+		if near > far {
+			return 0, false
+		}
+	}
+	return near, true
+}
+
+// raySegment returns the distance along the ray (with a unit
+// direction) at which it crosses the segment a -> b, if it does
+func raySegment(origin, dir, a, b *Vector) (float64, bool) {
+	edge := b.Clone().Sub(a)
+	denom := dir.Cross(edge)
+	if 0 == denom {
+		return 0, false
+	}
+	diff := a.Clone().Sub(origin)
+	t := diff.Cross(edge) / denom
+	u := diff.Cross(dir) / denom
+	if t < 0 || u < 0 || u > 1 {
+		return 0, false
+	}
+	return t, true
+}
+
+// rayTriangle returns the distance along the ray (with a unit
+// direction) at which it first touches the given triangle
+func rayTriangle(origin, dir *Vector, tri *Triangle) (float64, bool) {
+	if tri.Contains(origin) {
+		return 0, true
+	}
+	best, hit := math.Inf(1), false
+	for i := 0; i < 3; i++ {
+		if d, ok := raySegment(origin, dir, tri.Points[i], tri.Points[(i+1)%3]); ok && d < best {
+			best, hit = d, true
+		}
+	}
+	return best, hit
+}
@@ -0,0 +1,78 @@
+package geo2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// gridTriangles splits a grid of squares into two triangles each
+func gridTriangles(size int) *TriangleList {
+	tris := TriangleList{}
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			fx, fy := float64(x), float64(y)
+			tris = append(tris,
+				NewTriangle([]*Vector{NewVector(fx, fy), NewVector(fx+1, fy), NewVector(fx+1, fy+1)}),
+				NewTriangle([]*Vector{NewVector(fx, fy), NewVector(fx+1, fy+1), NewVector(fx, fy+1)}),
+			)
+		}
+	}
+	return &tris
+}
+
+func TestBVHTriangleAt(t *testing.T) {
+	tris := gridTriangles(20)
+	rng := rand.New(rand.NewSource(12))
+	for _, split := range []BVHSplit{BVHSplitSAH, BVHSplitMedian} {
+		bvh := NewBVH(tris, split)
+		for i := 0; i < 200; i++ {
+			p := NewVector(rng.Float64()*20, rng.Float64()*20)
+			found := bvh.TriangleAt(p)
+			if found < 0 || !(*tris)[found].Contains(p) {
+				t.Error("point inside the mesh should be found in a triangle")
+			}
+		}
+		if bvh.TriangleAt(NewVector(25, 5)) != -1 {
+			t.Error("point outside the mesh should not be found")
+		}
+	}
+}
+
+func TestBVHRaycast(t *testing.T) {
+	tris := TriangleList{
+		NewTriangle([]*Vector{NewVector(5, -1), NewVector(6, 0), NewVector(5, 1)}),
+		NewTriangle([]*Vector{NewVector(10, -1), NewVector(11, 0), NewVector(10, 1)}),
+		NewTriangle([]*Vector{NewVector(2, 5), NewVector(3, 5), NewVector(2, 6)}),
+	}
+	bvh := NewBVH(&tris, BVHSplitSAH)
+	index, dist := bvh.Raycast(NewVector(0, 0), NewVector(2, 0))
+	if index != 0 || math.Abs(dist-5) > 1e-9 {
+		t.Error("ray should hit the closest triangle first")
+	}
+	index, dist = bvh.Raycast(NewVector(20, 0), NewVector(-1, 0))
+	if index != 1 || math.Abs(dist-9) > 1e-9 {
+		t.Error("ray should hit the closest triangle from the other side")
+	}
+	if index, _ = bvh.Raycast(NewVector(0, 0), NewVector(0, -1)); index != -1 {
+		t.Error("ray pointing away should not hit anything")
+	}
+}
+
+func TestBVHQueryRectangleRefit(t *testing.T) {
+	tris := gridTriangles(10)
+	bvh := NewBVH(tris, BVHSplitMedian)
+	found := bvh.QueryRectangle(NewRectangle(2.5, 2.5, 1, 0.2))
+	if len(found) != 4 {
+		t.Error("rectangle should overlap the triangles of two squares")
+	}
+
+	tris.Transform(TranslationMatrix(100, 0))
+	if bvh.TriangleAt(NewVector(105.5, 5.2)) != -1 {
+		t.Error("stale hierarchy should not find the moved triangles")
+	}
+	bvh.Refit()
+	if found := bvh.TriangleAt(NewVector(105.5, 5.2)); found < 0 {
+		t.Error("refit hierarchy should find the moved triangles")
+	}
+}
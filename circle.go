@@ -0,0 +1,34 @@
+package geo2
+
+import "math"
+
+// Circle represents a 2D circle
+type Circle struct {
+	Center *Vector
+	Radius float64
+}
+
+// NewCircle creates a new circle with the given center and radius
+func NewCircle(center *Vector, radius float64) *Circle {
+	return &Circle{center, radius}
+}
+
+// Clone creates a copy of this circle
+func (c *Circle) Clone() *Circle {
+	return NewCircle(c.Center.Clone(), c.Radius)
+}
+
+// Contains returns true if the given point is within this circle
+func (c *Circle) Contains(vec *Vector) bool {
+	return vec.Clone().Sub(c.Center).LengthSqd() <= c.Radius*c.Radius
+}
+
+// Bounds returns the smallest rectangle containing this circle
+func (c *Circle) Bounds() *Rectangle {
+	return NewRectangle(c.Center.X-c.Radius, c.Center.Y-c.Radius, c.Radius*2, c.Radius*2)
+}
+
+// Area returns the area of this circle
+func (c *Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
@@ -0,0 +1,231 @@
+package geo2
+
+import "math"
+
+// ConvexDecomposition splits the area within this path (treated as a
+// closed loop) and outside of the given holes into convex pieces using
+// the Hertel-Mehlhorn algorithm. The area is triangulated and then
+// neighbouring pieces are merged wherever the result stays convex,
+// giving at most four times the fewest possible pieces. Each piece
+// is wound counter clockwise. Returns nil if the area cannot be
+// triangulated by TriangulateWithHoles
+func (path *Path) ConvexDecomposition(holes []*Path) []*Path {
+	tris := path.TriangulateWithHoles(holes)
+	if nil == tris {
+		return nil
+	}
+	pieces := make([][]*Vector, 0, len(*tris))
+	for _, tri := range *tris {
+		pieces = append(pieces, []*Vector{tri.Points[0], tri.Points[1], tri.Points[2]})
+	}
+
+	// pieces are joined along the diagonals they share, which run in
+	// opposite directions around each of them. Merged away pieces are
+	// left as nil so the indices held by the edge map stay valid
+	edges := make(map[[2]*Vector][2]int)
+	for p, piece := range pieces {
+		for i, a := range piece {
+			edges[[2]*Vector{a, piece[(i+1)%len(piece)]}] = [2]int{p, i}
+		}
+	}
+	count := len(pieces)
+	for p := range pieces {
+		// joining only widens the corners of a piece, so a diagonal
+		// which is rejected once never needs to be checked again
+		for i := 0; nil != pieces[p] && i < len(pieces[p]); i++ {
+			piece := pieces[p]
+			a, b := piece[i], piece[(i+1)%len(piece)]
+			other, ok := edges[[2]*Vector{b, a}]
+			if !ok || other[0] == p {
+				continue
+			}
+			join := joinPieces(piece, i, pieces[other[0]], other[1])
+			if !isConvex(join) {
+				continue
+			}
+			delete(edges, [2]*Vector{a, b})
+			delete(edges, [2]*Vector{b, a})
+			pieces[p], pieces[other[0]] = join, nil
+			for k, v := range join {
+				edges[[2]*Vector{v, join[(k+1)%len(join)]}] = [2]int{p, k}
+			}
+			count--
+			i = -1
+		}
+	}
+
+	result := make([]*Path, 0, count)
+	for _, piece := range pieces {
+		if nil == piece {
+			continue
+		}
+		clean := Path{}
+		for _, p := range piece {
+			clean = append(clean, p.Clone())
+		}
+		result = append(result, &clean)
+	}
+	return result
+}
+
+// joinPieces joins two pieces along the edge starting at index i of
+// the first, which is the edge starting at index j of the second
+func joinPieces(first []*Vector, i int, second []*Vector, j int) []*Vector {
+	joined := make([]*Vector, 0, len(first)+len(second)-2)
+	for k := 1; k <= len(first); k++ {
+		joined = append(joined, first[(i+k)%len(first)])
+	}
+	for k := 2; k < len(second); k++ {
+		joined = append(joined, second[(j+k)%len(second)])
+	}
+	return joined
+}
+
+// ApproximateConvexDecomposition splits the area within this path
+// (treated as a closed loop) into pieces which are convex to within the
+// given tolerance, using Bayazit's algorithm. The reflex corner which
+// sits deepest inside the convex hull of a piece is repeatedly resolved
+// by cutting the piece from that corner to another corner (or a new
+// point on the opposite edge) until every reflex corner is within the
+// tolerance of the hull. A tolerance of zero gives exactly convex
+// pieces. Each piece is wound counter clockwise, and pieces without
+// any area are left out, so a path with no area gives nil
+func (path *Path) ApproximateConvexDecomposition(tolerance float64) []*Path {
+	points := dedupeLoop(*path.Clone())
+	if len(points) < 3 {
+		return nil
+	}
+	var result []*Path
+	bayazit(counterClockwise(points), math.Max(tolerance, 0), &result, 0)
+	return result
+}
+
+// keepPiece adds a copy of the given piece
+// to the result unless it has no area
+func keepPiece(result *[]*Path, piece []*Vector) {
+	if 0 == signedArea(piece) {
+		return
+	}
+	outline := Path(piece)
+	*result = append(*result, outline.Clone())
+}
+
+// bayazitMaxDepth limits how many times a piece can be cut
+// to protect against degenerate input
+const bayazitMaxDepth = 64
+
+// bayazit decomposes the given counter clockwise polygon
+func bayazit(poly []*Vector, tolerance float64, result *[]*Path, depth int) {
+	n := len(poly)
+	at := func(i int) *Vector {
+		return poly[((i%n)+n)%n]
+	}
+
+	// find the reflex corner furthest inside the convex hull
+	outline := Path(poly)
+	hull := *outline.ConvexHull()
+	reflex, deepest := -1, tolerance
+	for i := 0; i < n; i++ {
+		if orient(at(i-1), at(i), at(i+1)) >= 0 {
+			continue
+		}
+		concavity := math.Inf(1)
+		for h, a := range hull {
+			concavity = math.Min(concavity, NewLine(a, hull[(h+1)%len(hull)]).DistanceToPoint(at(i), false))
+		}
+		if concavity > deepest || (0 == tolerance && -1 == reflex) {
+			reflex, deepest = i, concavity
+		}
+	}
+	if -1 == reflex || depth >= bayazitMaxDepth {
+		keepPiece(result, poly)
+		return
+	}
+
+	i := reflex
+	lowerDist, upperDist := math.Inf(1), math.Inf(1)
+	var lowerInt, upperInt *Vector
+	lowerIndex, upperIndex := -1, -1
+	for j := 0; j < n; j++ {
+		// extend the edges either side of the corner to find
+		// the closest edges they hit on the far side
+		if orient(at(i-1), at(i), at(j)) > 0 && orient(at(i-1), at(i), at(j-1)) <= 0 {
+			if p := NewLine(at(i-1), at(i)).Intersection(NewLine(at(j), at(j-1)), false); nil != p &&
+				orient(at(i+1), at(i), p) < 0 {
+				if d := p.Clone().Sub(at(i)).LengthSqd(); d < lowerDist {
+					lowerDist, lowerInt, lowerIndex = d, p, j
+				}
+			}
+		}
+		if orient(at(i+1), at(i), at(j+1)) > 0 && orient(at(i+1), at(i), at(j)) <= 0 {
+			if p := NewLine(at(i+1), at(i)).Intersection(NewLine(at(j), at(j+1)), false); nil != p &&
+				orient(at(i-1), at(i), p) > 0 {
+				if d := p.Clone().Sub(at(i)).LengthSqd(); d < upperDist {
+					upperDist, upperInt, upperIndex = d, p, j
+				}
+			}
+		}
+	}
+	if -1 == lowerIndex || -1 == upperIndex {
+		keepPiece(result, poly)
+		return
+	}
+
+	var lower, upper []*Vector
+	if lowerIndex == (upperIndex+1)%n {
+		// no corners can be seen between the two edges
+		// so cut to the middle of the opposite edge
+		p := lowerInt.Clone().Add(upperInt).MultiplyScalar(0.5)
+		if i < upperIndex {
+			lower = append(append(lower, poly[i:upperIndex+1]...), p)
+			upper = append(upper, p)
+			if 0 != lowerIndex {
+				upper = append(upper, poly[lowerIndex:]...)
+			}
+			upper = append(upper, poly[:i+1]...)
+		} else {
+			if 0 != i {
+				lower = append(lower, poly[i:]...)
+			}
+			lower = append(append(lower, poly[:upperIndex+1]...), p)
+			upper = append(append(upper, p), poly[lowerIndex:i+1]...)
+		}
+	} else {
+		// cut to the closest visible corner between the two edges
+		if lowerIndex > upperIndex {
+			upperIndex += n
+		}
+		closest, closestDist := -1, math.Inf(1)
+		for j := lowerIndex; j <= upperIndex; j++ {
+			if orient(at(i-1), at(i), at(j)) >= 0 && orient(at(i+1), at(i), at(j)) <= 0 &&
+				!at(j).Compare(at(i)) && !loopBlocks(poly, at(i), at(j)) {
+				if d := at(j).Clone().Sub(at(i)).LengthSqd(); d < closestDist {
+					closest, closestDist = j%n, d
+				}
+			}
+		}
+		if -1 == closest {
+			keepPiece(result, poly)
+			return
+		}
+		if i < closest {
+			lower = append(lower, poly[i:closest+1]...)
+			if 0 != closest {
+				upper = append(upper, poly[closest:]...)
+			}
+			upper = append(upper, poly[:i+1]...)
+		} else {
+			if 0 != i {
+				lower = append(lower, poly[i:]...)
+			}
+			lower = append(lower, poly[:closest+1]...)
+			upper = append(upper, poly[closest:i+1]...)
+		}
+	}
+
+	for _, piece := range [][]*Vector{lower, upper} {
+		if piece = dedupeLoop(piece); len(piece) >= 3 {
+			bayazit(piece, tolerance, result, depth+1)
+		}
+	}
+}
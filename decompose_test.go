@@ -0,0 +1,67 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+// checkPieces makes sure the pieces are convex and cover the given area
+func checkPieces(t *testing.T, pieces []*Path, area float64) {
+	t.Helper()
+	total := 0.0
+	for _, piece := range pieces {
+		if !isConvex(*piece) {
+			t.Error("pieces should be convex")
+		}
+		total += signedArea(*piece)
+	}
+	if math.Abs(total-area) > 1e-9 {
+		t.Error("pieces should cover the area")
+	}
+}
+
+func TestConvexDecomposition(t *testing.T) {
+	ell := &Path{NewVector(0, 0), NewVector(4, 0), NewVector(4, 2), NewVector(2, 2), NewVector(2, 4), NewVector(0, 4)}
+	pieces := ell.ConvexDecomposition(nil)
+	if 2 != len(pieces) {
+		t.Error("expected the L shape to be split in 2")
+	}
+	checkPieces(t, pieces, 12)
+
+	holed := NewRectangle(0, 0, 10, 10).ToPath().ConvexDecomposition([]*Path{NewRectangle(4, 4, 2, 2).ToPath()})
+	if len(holed) < 4 || len(holed) > 8 {
+		t.Error("expected 4 to 8 pieces around the hole")
+	}
+	checkPieces(t, holed, 96)
+}
+
+func TestApproximateConvexDecomposition(t *testing.T) {
+	comb := &Path{NewVector(0, 0), NewVector(10, 0), NewVector(10, 5)}
+	for x := 9.0; x > 0; x -= 2 {
+		comb.Append(NewVector(x, 1))
+		comb.Append(NewVector(x-1, 5))
+	}
+	area := signedArea(*comb)
+
+	exact := comb.ApproximateConvexDecomposition(0)
+	checkPieces(t, exact, area)
+	if len(exact) < 6 {
+		t.Error("expected each tooth of the comb in its own piece")
+	}
+
+	rough := comb.ApproximateConvexDecomposition(10)
+	if 1 != len(rough) {
+		t.Error("a large tolerance should keep the comb whole")
+	}
+
+	notched := &Path{NewVector(0, 0), NewVector(10, 0), NewVector(10, 4), NewVector(5, 3.9), NewVector(0, 4)}
+	if pieces := notched.ApproximateConvexDecomposition(0.5); 1 != len(pieces) {
+		t.Error("a shallow notch within the tolerance should not be cut")
+	}
+	checkPieces(t, notched.ApproximateConvexDecomposition(0), signedArea(*notched))
+
+	collinear := &Path{NewVector(0, 0), NewVector(1, 0), NewVector(2, 0)}
+	if nil != collinear.ApproximateConvexDecomposition(0) {
+		t.Error("path with no area should not have any pieces")
+	}
+}
@@ -0,0 +1,128 @@
+package geo2
+
+import "math"
+
+// delaunayMesh is an incremental Delaunay triangulation built
+// with the Bowyer-Watson algorithm. The first three points form a
+// super triangle surrounding every point which will be inserted
+type delaunayMesh struct {
+	points    []*Vector
+	triangles []*delaunayTriangle
+}
+
+// delaunayTriangle is a counter clockwise triangle
+// of point indices along with its circumcircle
+type delaunayTriangle struct {
+	v         [3]int
+	center    *Vector
+	radiusSqd float64
+}
+
+// newDelaunayMesh creates an empty triangulation able
+// to hold points within the given bounds
+func newDelaunayMesh(bounds *Rectangle) *delaunayMesh {
+	bounds = bounds.Clone().Normalize()
+	size := math.Max(math.Max(bounds.Width, bounds.Height), 1)
+	center := bounds.Center()
+	mesh := &delaunayMesh{points: []*Vector{
+		NewVector(center.X-20*size, center.Y-20*size),
+		NewVector(center.X+20*size, center.Y-20*size),
+		NewVector(center.X, center.Y+20*size),
+	}}
+	mesh.addTriangle(0, 1, 2)
+	return mesh
+}
+
+// addTriangle adds the triangle with the given
+// point indices, which must be counter clockwise
+func (mesh *delaunayMesh) addTriangle(a, b, c int) *delaunayTriangle {
+	tri := &delaunayTriangle{v: [3]int{a, b, c}}
+	tri.center = circumcenter(mesh.points[a], mesh.points[b], mesh.points[c])
+	if nil == tri.center {
+		tri.center = mesh.points[a].Clone()
+		tri.radiusSqd = math.Inf(1)
+	} else {
+		tri.radiusSqd = tri.center.Clone().Sub(mesh.points[a]).LengthSqd()
+	}
+	mesh.triangles = append(mesh.triangles, tri)
+	return tri
+}
+
+// insert adds the given point to the triangulation, returning its
+// index (or the index of an existing point at the same position)
+func (mesh *delaunayMesh) insert(point *Vector) int {
+	for i, p := range mesh.points {
+		if p.Compare(point) {
+			return i
+		}
+	}
+	index := len(mesh.points)
+	mesh.points = append(mesh.points, point)
+
+	// remove every triangle whose circumcircle contains the point,
+	// keeping the edges around the hole which is left behind in
+	// order so that the triangulation is repeatable
+	var edges [][2]int
+	interior := make(map[[2]int]bool)
+	kept := mesh.triangles[:0]
+	for _, tri := range mesh.triangles {
+		if tri.center.Clone().Sub(point).LengthSqd() >= tri.radiusSqd {
+			kept = append(kept, tri)
+			continue
+		}
+		for j := 0; j < 3; j++ {
+			edge := [2]int{tri.v[j], tri.v[(j+1)%3]}
+			edges = append(edges, edge)
+			interior[edge] = true
+		}
+	}
+	mesh.triangles = kept
+	for _, edge := range edges {
+		// edges shared by two removed triangles run both ways
+		if !interior[[2]int{edge[1], edge[0]}] {
+			mesh.addTriangle(edge[0], edge[1], index)
+		}
+	}
+	return index
+}
+
+// result returns the triangles which do not use the super triangle,
+// with point indices offset to exclude it
+func (mesh *delaunayMesh) result() [][3]int {
+	var result [][3]int
+	for _, tri := range mesh.triangles {
+		if tri.v[0] < 3 || tri.v[1] < 3 || tri.v[2] < 3 {
+			continue
+		}
+		result = append(result, [3]int{tri.v[0] - 3, tri.v[1] - 3, tri.v[2] - 3})
+	}
+	return result
+}
+
+// delaunay returns the Delaunay triangulation of the given
+// points as counter clockwise triples of indices into them.
+// Duplicate points should be removed beforehand
+func delaunay(points []*Vector) [][3]int {
+	if len(points) < 3 {
+		return nil
+	}
+	bounds := NewRectangleFromPoints(points)
+	mesh := newDelaunayMesh(bounds)
+	for _, p := range points {
+		mesh.insert(p)
+	}
+	return mesh.result()
+}
+
+// circumcenter returns the center of the circle passing
+// through all three points, or nil if they are collinear
+func circumcenter(a, b, c *Vector) *Vector {
+	bx, by := b.X-a.X, b.Y-a.Y
+	cx, cy := c.X-a.X, c.Y-a.Y
+	d := 2 * (bx*cy - by*cx)
+	if 0 == d {
+		return nil
+	}
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	return NewVector(a.X+(cy*b2-by*c2)/d, a.Y+(bx*c2-cx*b2)/d)
+}
@@ -0,0 +1,34 @@
+package geo2
+
+import "testing"
+
+func TestDelaunay(t *testing.T) {
+	points := randomPoints(5, 200)
+	triangles := delaunay(points)
+	if 0 == len(triangles) {
+		t.Fatal("expected triangles")
+	}
+	for _, tri := range triangles {
+		a, b, c := points[tri[0]], points[tri[1]], points[tri[2]]
+		if orient(a, b, c) <= 0 {
+			t.Error("triangles should be counter clockwise")
+		}
+		center := circumcenter(a, b, c)
+		radius := center.Clone().Sub(a).LengthSqd()
+		for i, p := range points {
+			if i != tri[0] && i != tri[1] && i != tri[2] && center.Clone().Sub(p).LengthSqd() < radius*(1-1e-9) {
+				t.Fatal("no point should lie within a circumcircle")
+			}
+		}
+	}
+
+	area := 0.0
+	for _, tri := range triangles {
+		area += orient(points[tri[0]], points[tri[1]], points[tri[2]]) / 2
+	}
+	cloud := Path(points)
+	hull := cloud.ConvexHull()
+	if hullArea := signedArea(*hull); area < hullArea*(1-1e-9) || area > hullArea*(1+1e-9) {
+		t.Error("triangles should cover the convex hull")
+	}
+}
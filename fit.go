@@ -0,0 +1,231 @@
+package geo2
+
+import (
+	"math"
+	"math/rand"
+)
+
+// FitFunc is the signature shared by the transform estimators. It
+// returns the transform which best maps the src points onto the dst
+// points along with the RMS residual of the fit, or nil if the
+// transform cannot be determined. Weights may be nil to weight
+// every correspondence equally
+type FitFunc func(src, dst []*Vector, weights []float64) (*Matrix3, float64)
+
+// FitRigid finds the rotation and translation which best maps the src
+// points onto the matching dst points in the least squares sense.
+// Returns nil if the sets do not match in length or fewer than
+// two correspondences (with non-zero weight) are given
+func FitRigid(src, dst []*Vector, weights []float64) (*Matrix3, float64) {
+	return fitOrthogonal(src, dst, weights, false)
+}
+
+// FitSimilarity finds the rotation, uniform scale and translation which
+// best maps the src points onto the matching dst points in the least
+// squares sense (Umeyama / Procrustes). Returns nil if the sets do not
+// match in length, fewer than two correspondences are given or all
+// of the src points are coincident
+func FitSimilarity(src, dst []*Vector, weights []float64) (*Matrix3, float64) {
+	return fitOrthogonal(src, dst, weights, true)
+}
+
+// FitAffine finds the full affine transform which best maps the src
+// points onto the matching dst points in the least squares sense.
+// Returns nil if the sets do not match in length or the src points
+// do not contain at least three non-collinear points
+func FitAffine(src, dst []*Vector, weights []float64) (*Matrix3, float64) {
+	if !validFitInput(src, dst, weights, 3) {
+		return nil, 0
+	}
+
+	ata := [][]float64{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}
+	atx := []float64{0, 0, 0}
+	aty := []float64{0, 0, 0}
+	for i, s := range src {
+		w := fitWeight(weights, i)
+		row := [3]float64{s.X, s.Y, 1}
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				ata[j][k] += w * row[j] * row[k]
+			}
+			atx[j] += w * row[j] * dst[i].X
+			aty[j] += w * row[j] * dst[i].Y
+		}
+	}
+
+	px := solveLinear(ata, atx, 1e-12)
+	py := solveLinear(ata, aty, 1e-12)
+	if px == nil || py == nil {
+		return nil, 0
+	}
+	m := &Matrix3{
+		{px[0], px[1], px[2]},
+		{py[0], py[1], py[2]},
+		{0, 0, 1},
+	}
+	return m, FitResidual(m, src, dst, weights)
+}
+
+// FitResidual returns the (weighted) RMS distance between the src
+// points transformed by the given matrix and the matching dst points
+func FitResidual(m *Matrix3, src, dst []*Vector, weights []float64) float64 {
+	var sum, total float64
+	for i, s := range src {
+		w := fitWeight(weights, i)
+		sum += w * s.Clone().MultiplyMatrix(m).Sub(dst[i]).LengthSqd()
+		total += w
+	}
+	if total == 0 {
+		return 0
+	}
+	return math.Sqrt(sum / total)
+}
+
+// FitRANSAC robustly estimates a transform in the presence of outliers
+// by repeatedly fitting random minimal subsets of samples points with
+// the given estimator and keeping the model which agrees with the most
+// correspondences (those mapped to within threshold of their dst point).
+// The final transform is refit to all of the inliers of the best model.
+//
+// Returns the transform, which correspondences were considered inliers
+// and the RMS residual of the inliers, or nil if no model was found.
+// If rng is nil a fixed seed is used so results are repeatable
+func FitRANSAC(fit FitFunc, samples int, src, dst []*Vector,
+	threshold float64, iterations int, rng *rand.Rand) (*Matrix3, []bool, float64) {
+	if len(src) != len(dst) || len(src) < samples || samples <= 0 {
+		return nil, nil, 0
+	}
+	if nil == rng {
+		rng = rand.New(rand.NewSource(1))
+	}
+
+	var best []bool
+	bestCount := 0
+	bestError := math.Inf(1)
+	subSrc := make([]*Vector, samples)
+	subDst := make([]*Vector, samples)
+	for iter := 0; iter < iterations; iter++ {
+		for i, idx := range rng.Perm(len(src))[:samples] {
+			subSrc[i] = src[idx]
+			subDst[i] = dst[idx]
+		}
+		m, _ := fit(subSrc, subDst, nil)
+		if nil == m {
+			continue
+		}
+		inliers, count, err := ransacInliers(m, src, dst, threshold)
+		if count > bestCount || (count == bestCount && err < bestError) {
+			best, bestCount, bestError = inliers, count, err
+		}
+	}
+	if bestCount < samples {
+		return nil, nil, 0
+	}
+
+	var inSrc, inDst []*Vector
+	for i, inlier := range best {
+		if inlier {
+			inSrc = append(inSrc, src[i])
+			inDst = append(inDst, dst[i])
+		}
+	}
+	m, rms := fit(inSrc, inDst, nil)
+	if nil == m {
+		return nil, nil, 0
+	}
+	return m, best, rms
+}
+
+// ransacInliers finds the correspondences which the given transform
+// maps to within threshold of their destination, and returns them
+// along with their count and summed squared error
+func ransacInliers(m *Matrix3, src, dst []*Vector, threshold float64) ([]bool, int, float64) {
+	inliers := make([]bool, len(src))
+	count := 0
+	sum := 0.0
+	limit := threshold * threshold
+	for i, s := range src {
+		d := s.Clone().MultiplyMatrix(m).Sub(dst[i]).LengthSqd()
+		if d <= limit {
+			inliers[i] = true
+			count++
+			sum += d
+		}
+	}
+	return inliers, count, sum
+}
+
+// fitOrthogonal implements the closed form 2d solution for
+// rigid and similarity transforms
+func fitOrthogonal(src, dst []*Vector, weights []float64, scaled bool) (*Matrix3, float64) {
+	if !validFitInput(src, dst, weights, 2) {
+		return nil, 0
+	}
+
+	srcCenter := NewVector(0, 0)
+	dstCenter := NewVector(0, 0)
+	total := 0.0
+	for i := range src {
+		w := fitWeight(weights, i)
+		srcCenter.Add(src[i].Clone().MultiplyScalar(w))
+		dstCenter.Add(dst[i].Clone().MultiplyScalar(w))
+		total += w
+	}
+	srcCenter.DivideScalar(total)
+	dstCenter.DivideScalar(total)
+
+	var dot, cross, variance float64
+	for i := range src {
+		w := fitWeight(weights, i)
+		a := src[i].Clone().Sub(srcCenter)
+		b := dst[i].Clone().Sub(dstCenter)
+		dot += w * a.Dot(b)
+		cross += w * a.Cross(b)
+		variance += w * a.LengthSqd()
+	}
+
+	angle := math.Atan2(cross, dot)
+	scale := 1.0
+	if scaled {
+		if variance == 0 {
+			return nil, 0
+		}
+		scale = math.Hypot(dot, cross) / variance
+	}
+
+	m := RotationMatrix(angle)
+	for row := 0; row < 2; row++ {
+		m[row][0] *= scale
+		m[row][1] *= scale
+	}
+	offset := srcCenter.Clone().MultiplyMatrix(m)
+	m[0][2] = dstCenter.X - offset.X
+	m[1][2] = dstCenter.Y - offset.Y
+	return m, FitResidual(m, src, dst, weights)
+}
+
+// validFitInput checks that the given correspondences and
+// weights line up, and that at least min of them have weight
+func validFitInput(src, dst []*Vector, weights []float64, min int) bool {
+	if len(src) != len(dst) || (nil != weights && len(weights) != len(src)) {
+		return false
+	}
+	count := 0
+	for i := range src {
+		w := fitWeight(weights, i)
+		if w < 0 {
+			return false
+		}
+		if w > 0 {
+			count++
+		}
+	}
+	return count >= min
+}
+
+func fitWeight(weights []float64, i int) float64 {
+	if nil == weights {
+		return 1
+	}
+	return weights[i]
+}
@@ -0,0 +1,89 @@
+package geo2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func fitPoints() []*Vector {
+	return []*Vector{
+		NewVector(0, 0), NewVector(4, 0), NewVector(4, 2),
+		NewVector(1, 3), NewVector(-2, 1), NewVector(3, -1),
+	}
+}
+
+func transformPoints(points []*Vector, m *Matrix3) []*Vector {
+	result := make([]*Vector, len(points))
+	for i, p := range points {
+		result[i] = p.Clone().MultiplyMatrix(m)
+	}
+	return result
+}
+
+func TestFitRigid(t *testing.T) {
+	expected := TranslationMatrix(3, -2).Multiply(RotationMatrix(0.7))
+	src := fitPoints()
+	m, rms := FitRigid(src, transformPoints(src, expected), nil)
+	if nil == m || !m.CloseEnough(expected, 0.0001) || rms > 1e-9 {
+		t.Error("rigid fit should recover the exact transform")
+	}
+}
+
+func TestFitSimilarity(t *testing.T) {
+	expected := TranslationMatrix(1, 5).
+		Multiply(RotationMatrix(-1.2)).
+		Multiply(ScaleMatrix(2.5, 2.5))
+	src := fitPoints()
+	m, rms := FitSimilarity(src, transformPoints(src, expected), nil)
+	if nil == m || !m.CloseEnough(expected, 0.0001) || rms > 1e-9 {
+		t.Error("similarity fit should recover the exact transform")
+	}
+}
+
+func TestFitAffine(t *testing.T) {
+	expected := &Matrix3{{1.5, 0.3, -2}, {0.2, 0.8, 4}, {0, 0, 1}}
+	src := fitPoints()
+	m, rms := FitAffine(src, transformPoints(src, expected), nil)
+	if nil == m || !m.CloseEnough(expected, 0.0001) || rms > 1e-9 {
+		t.Error("affine fit should recover the exact transform")
+	}
+	if m, _ := FitAffine(src[:2], src[:2], nil); nil != m {
+		t.Error("affine fit should require three points")
+	}
+}
+
+func TestFitWeights(t *testing.T) {
+	src := []*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(5, 5)}
+	dst := []*Vector{NewVector(1, 0), NewVector(2, 0), NewVector(-10, 3)}
+	m, rms := FitRigid(src, dst, []float64{1, 1, 0})
+	if nil == m || !m.CloseEnough(TranslationMatrix(1, 0), 0.0001) || rms > 1e-9 {
+		t.Error("zero weighted correspondences should be ignored")
+	}
+}
+
+func TestFitRANSAC(t *testing.T) {
+	expected := TranslationMatrix(-4, 2).Multiply(RotationMatrix(0.3))
+	rng := rand.New(rand.NewSource(7))
+	var src, dst []*Vector
+	for i := 0; i < 40; i++ {
+		p := NewVector(rng.Float64()*100, rng.Float64()*100)
+		src = append(src, p)
+		dst = append(dst, p.Clone().MultiplyMatrix(expected))
+	}
+	for i := 0; i < 10; i++ {
+		dst[i*3].Add(NewVector(20+rng.Float64()*30, -30))
+	}
+	m, inliers, rms := FitRANSAC(FitRigid, 2, src, dst, 0.5, 100, nil)
+	if nil == m || !m.CloseEnough(expected, 0.0001) || rms > 1e-9 {
+		t.Error("ransac should recover the transform despite outliers")
+	}
+	for i, inlier := range inliers {
+		if inlier == (i%3 == 0 && i < 30) {
+			t.Error("outliers should be rejected")
+		}
+	}
+	if _, rms := FitRigid(src, dst, nil); rms < 1 || math.IsNaN(rms) {
+		t.Error("plain least squares should be affected by outliers")
+	}
+}
@@ -0,0 +1,330 @@
+package geo2
+
+import "math"
+
+// Support is implemented by convex shapes which can report their
+// furthest point in any direction (their support mapping). Any pair
+// of Support shapes can be tested with GJK and EPA. Shapes which are
+// not convex are treated as their convex hull
+type Support interface {
+	// Support returns the point of this shape which is
+	// furthest in the given (not necessarily unit) direction
+	Support(direction *Vector) *Vector
+}
+
+// TransformedShape is a Support shape placed by an affine transform,
+// so that shapes can be tested in world space without moving them
+type TransformedShape struct {
+	Shape     Support
+	Transform *Matrix3
+}
+
+// Support returns the furthest point of the transformed
+// shape in the given direction
+func (ts *TransformedShape) Support(direction *Vector) *Vector {
+	m := ts.Transform
+	// directions transform by the transpose of the linear part
+	local := NewVector(
+		direction.X*m[0][0]+direction.Y*m[1][0],
+		direction.X*m[0][1]+direction.Y*m[1][1],
+	)
+	return ts.Shape.Support(local).Clone().MultiplyMatrix(m)
+}
+
+// Support returns the furthest point of this
+// circle in the given direction
+func (c *Circle) Support(direction *Vector) *Vector {
+	if 0 == direction.LengthSqd() {
+		return NewVector(c.Center.X+c.Radius, c.Center.Y)
+	}
+	return direction.Clone().Normalize().MultiplyScalar(c.Radius).Add(c.Center)
+}
+
+// Support returns the furthest point of this
+// triangle in the given direction
+func (t *Triangle) Support(direction *Vector) *Vector {
+	return supportPoint(t.Points, direction)
+}
+
+// Support returns the furthest corner of this
+// rectangle in the given direction
+func (rect *Rectangle) Support(direction *Vector) *Vector {
+	return supportPoint(rect.Corners(), direction)
+}
+
+// Support returns the furthest corner of this oriented
+// rectangle in the given direction
+func (o *OrientedRectangle) Support(direction *Vector) *Vector {
+	return supportPoint(o.Corners(), direction)
+}
+
+// Support returns the furthest point of this path in the
+// given direction (treating the path as its convex hull)
+func (path *Path) Support(direction *Vector) *Vector {
+	return supportPoint(*path, direction)
+}
+
+// supportPoint returns a copy of the point furthest in the given direction
+func supportPoint(points []*Vector, direction *Vector) *Vector {
+	best := points[0]
+	bestDot := best.Dot(direction)
+	for _, p := range points[1:] {
+		if d := p.Dot(direction); d > bestDot {
+			best, bestDot = p, d
+		}
+	}
+	return best.Clone()
+}
+
+// GJKResult is the outcome of a GJK distance query
+type GJKResult struct {
+	// Overlap is true if the shapes overlap or touch
+	Overlap bool
+	// Distance is the distance between the shapes, or zero if they overlap
+	Distance float64
+	// PointA and PointB are the closest points on each shape
+	// (only meaningful when the shapes do not overlap)
+	PointA *Vector
+	PointB *Vector
+}
+
+// gjkVertex is a point of the minkowski difference a - b,
+// along with the support points which produced it
+type gjkVertex struct {
+	p, a, b *Vector
+}
+
+func gjkSupport(a, b Support, direction *Vector) gjkVertex {
+	sa := a.Support(direction)
+	sb := b.Support(direction.Clone().Negate())
+	return gjkVertex{sa.Clone().Sub(sb), sa, sb}
+}
+
+// GJK computes the distance and closest points between two convex
+// shapes using the Gilbert-Johnson-Keerthi algorithm
+func GJK(a, b Support) *GJKResult {
+	result, _ := gjk(a, b)
+	return result
+}
+
+// GJKOverlap returns true if the given convex shapes overlap or touch
+func GJKOverlap(a, b Support) bool {
+	return GJK(a, b).Overlap
+}
+
+// gjk runs GJK, also returning the final simplex for use by EPA
+func gjk(a, b Support) (*GJKResult, []gjkVertex) {
+	simplex := []gjkVertex{gjkSupport(a, b, NewVector(1, 0))}
+	v := simplex[0].p.Clone()
+	weights := []float64{1}
+	// shapes closer than the tolerance are touching, and the search
+	// stops once a step improves the squared distance by less than
+	// the relative tolerance (an absolute test stops early for curved
+	// shapes such as circles, giving imprecise closest points)
+	const tolerance = 1e-10
+	const relativeTolerance = 1e-12
+
+	for iter := 0; iter < 64; iter++ {
+		vv := v.LengthSqd()
+		if vv <= tolerance*tolerance {
+			return &GJKResult{Overlap: true}, simplex
+		}
+		w := gjkSupport(a, b, v.Clone().Negate())
+		// stop once the new point gets no closer to the origin
+		if vv-v.Dot(w.p) <= math.Max(relativeTolerance*vv, tolerance*tolerance) {
+			break
+		}
+		duplicate := false
+		for _, s := range simplex {
+			if s.p.Compare(w.p) {
+				duplicate = true
+			}
+		}
+		if duplicate {
+			break
+		}
+		simplex = append(simplex, w)
+		var inside bool
+		simplex, weights, inside = gjkReduce(simplex)
+		if inside {
+			return &GJKResult{Overlap: true}, simplex
+		}
+		v = NewVector(0, 0)
+		for i, s := range simplex {
+			v.Add(s.p.Clone().MultiplyScalar(weights[i]))
+		}
+	}
+
+	pointA := NewVector(0, 0)
+	pointB := NewVector(0, 0)
+	for i, s := range simplex {
+		pointA.Add(s.a.Clone().MultiplyScalar(weights[i]))
+		pointB.Add(s.b.Clone().MultiplyScalar(weights[i]))
+	}
+	return &GJKResult{
+		Distance: v.Length(),
+		PointA:   pointA,
+		PointB:   pointB,
+	}, simplex
+}
+
+// gjkReduce finds the closest point of the given simplex to the
+// origin, returning the smallest sub simplex which contains it and
+// its barycentric weights, or true if the simplex contains the origin
+func gjkReduce(simplex []gjkVertex) ([]gjkVertex, []float64, bool) {
+	switch len(simplex) {
+	case 2:
+		t := segmentClosestToOrigin(simplex[0].p, simplex[1].p)
+		switch {
+		case t <= 0:
+			return simplex[:1], []float64{1}, false
+		case t >= 1:
+			return simplex[1:], []float64{1}, false
+		}
+		return simplex, []float64{1 - t, t}, false
+	case 3:
+		a, b, c := simplex[0].p, simplex[1].p, simplex[2].p
+		area := orient(a, b, c)
+		if 0 != area {
+			// barycentric coordinates of the origin
+			u := b.Cross(c) / area
+			v := c.Cross(a) / area
+			w := a.Cross(b) / area
+			if u >= 0 && v >= 0 && w >= 0 {
+				return simplex, []float64{u, v, w}, true
+			}
+		}
+		// otherwise the closest point is on one of the edges
+		var best []gjkVertex
+		var bestWeights []float64
+		bestDist := math.Inf(1)
+		for _, edge := range [][2]int{{0, 1}, {1, 2}, {2, 0}} {
+			sub := []gjkVertex{simplex[edge[0]], simplex[edge[1]]}
+			sub, weights, _ := gjkReduce(sub)
+			p := NewVector(0, 0)
+			for i, s := range sub {
+				p.Add(s.p.Clone().MultiplyScalar(weights[i]))
+			}
+			if d := p.LengthSqd(); d < bestDist {
+				best, bestWeights, bestDist = sub, weights, d
+			}
+		}
+		return best, bestWeights, false
+	}
+	return simplex, []float64{1}, false
+}
+
+// segmentClosestToOrigin returns the (unclamped) position along
+// a -> b of the point closest to the origin
+func segmentClosestToOrigin(a, b *Vector) float64 {
+	ab := b.Clone().Sub(a)
+	lengthSqd := ab.LengthSqd()
+	if 0 == lengthSqd {
+		return 0
+	}
+	return -a.Dot(ab) / lengthSqd
+}
+
+// EPA computes the penetration of two overlapping convex shapes using
+// the expanding polytope algorithm, returning nil if they do not
+// overlap or no penetration direction can be found. The manifold holds a single contact point, which is the
+// deepest point of A within B
+func EPA(a, b Support) *Manifold {
+	result, simplex := gjk(a, b)
+	if !result.Overlap {
+		return nil
+	}
+	polytope := epaInitialPolytope(a, b, simplex)
+	if nil == polytope {
+		// the shapes have no area, so only touch
+		return &Manifold{Normal: NewVector(1, 0), MTV: NewVector(0, 0), Contacts: []*Vector{simplex[0].a.Clone()}}
+	}
+
+	const tolerance = 1e-9
+	var normal *Vector
+	var depth float64
+	var edge int
+	for iter := 0; iter < 128; iter++ {
+		var ok bool
+		if edge, normal, depth, ok = epaClosestEdge(polytope); !ok {
+			return nil
+		}
+		w := gjkSupport(a, b, normal)
+		if w.p.Dot(normal)-depth <= tolerance*math.Max(1, depth) {
+			break
+		}
+		polytope = append(polytope[:edge+1], append([]gjkVertex{w}, polytope[edge+1:]...)...)
+	}
+
+	// find the point on the closest edge nearest the origin and
+	// use its weights to recover the contact point on A
+	v1 := polytope[edge]
+	v2 := polytope[(edge+1)%len(polytope)]
+	t := math.Min(1, math.Max(0, segmentClosestToOrigin(v1.p, v2.p)))
+	contact := v1.a.Clone().MultiplyScalar(1 - t).Add(v2.a.Clone().MultiplyScalar(t))
+
+	// the edge normal points out of a - b, moving b along it separates them
+	return &Manifold{
+		Normal:   normal,
+		Depth:    depth,
+		MTV:      normal.Clone().MultiplyScalar(depth),
+		Contacts: []*Vector{contact},
+	}
+}
+
+// epaInitialPolytope grows the final GJK simplex into a counter
+// clockwise triangle containing the origin, or returns nil if the
+// minkowski difference has no area
+func epaInitialPolytope(a, b Support, simplex []gjkVertex) []gjkVertex {
+	polytope := append([]gjkVertex(nil), simplex...)
+	if 1 == len(polytope) {
+		for _, dir := range []*Vector{NewVector(1, 0), NewVector(-1, 0), NewVector(0, 1), NewVector(0, -1)} {
+			w := gjkSupport(a, b, dir)
+			if !w.p.Compare(polytope[0].p) {
+				polytope = append(polytope, w)
+				break
+			}
+		}
+		if 1 == len(polytope) {
+			return nil
+		}
+	}
+	if 2 == len(polytope) {
+		edge := polytope[1].p.Clone().Sub(polytope[0].p)
+		normal := NewVector(-edge.Y, edge.X)
+		w := gjkSupport(a, b, normal)
+		if math.Abs(w.p.Clone().Sub(polytope[0].p).Dot(normal)) <= 1e-12*normal.LengthSqd() {
+			w = gjkSupport(a, b, normal.Negate())
+			if math.Abs(w.p.Clone().Sub(polytope[0].p).Dot(normal)) <= 1e-12*normal.LengthSqd() {
+				return nil
+			}
+		}
+		polytope = append(polytope, w)
+	}
+	if signedArea([]*Vector{polytope[0].p, polytope[1].p, polytope[2].p}) < 0 {
+		polytope[1], polytope[2] = polytope[2], polytope[1]
+	}
+	return polytope
+}
+
+// epaClosestEdge returns the edge of the counter clockwise polytope
+// closest to the origin, along with its outward normal and distance,
+// or false if every edge is degenerate
+func epaClosestEdge(polytope []gjkVertex) (int, *Vector, float64, bool) {
+	best := 0
+	var bestNormal *Vector
+	bestDist := math.Inf(1)
+	for i := range polytope {
+		a := polytope[i].p
+		b := polytope[(i+1)%len(polytope)].p
+		normal := edgeNormal(a, b)
+		if 0 == normal.LengthSqd() {
+			continue
+		}
+		normal.Normalize()
+		if d := normal.Dot(a); d < bestDist {
+			best, bestNormal, bestDist = i, normal, d
+		}
+	}
+	return best, bestNormal, bestDist, nil != bestNormal
+}
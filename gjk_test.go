@@ -0,0 +1,83 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGJKDistance(t *testing.T) {
+	a := NewCircle(NewVector(0, 0), 1)
+	b := NewCircle(NewVector(5, 0), 2)
+	result := GJK(a, b)
+	if result.Overlap || math.Abs(result.Distance-2) > 1e-6 {
+		t.Error("distance between circles should be the gap between them")
+	}
+	if !result.PointA.CloseEnough(NewVector(1, 0), 0.001) || !result.PointB.CloseEnough(NewVector(3, 0), 0.001) {
+		t.Error("closest points should be on the facing sides of the circles")
+	}
+
+	rect := NewRectangle(0, 0, 2, 2)
+	tri := NewTriangle([]*Vector{NewVector(4, 3), NewVector(6, 3), NewVector(5, 6)})
+	result = GJK(rect, tri)
+	if result.Overlap || math.Abs(result.Distance-math.Sqrt(5)) > 1e-9 {
+		t.Error("distance should be from the rectangle corner to the triangle corner")
+	}
+	if !result.PointA.CloseEnough(NewVector(2, 2), 0.0001) || !result.PointB.CloseEnough(NewVector(4, 3), 0.0001) {
+		t.Error("closest points should be the nearest corners")
+	}
+
+	edge := &Path{NewVector(3, -5), NewVector(3, 5), NewVector(8, 0)}
+	if result = GJK(rect, edge); math.Abs(result.Distance-1) > 1e-9 {
+		t.Error("distance to an edge should be perpendicular")
+	}
+}
+
+func TestGJKCircleClosestPoints(t *testing.T) {
+	result := GJK(NewCircle(NewVector(0, 0), 1), NewCircle(NewVector(3, 4), 1))
+	if result.PointA.Clone().Sub(NewVector(0.6, 0.8)).Length() > 1e-6 ||
+		result.PointB.Clone().Sub(NewVector(2.4, 3.2)).Length() > 1e-6 {
+		t.Error("closest points on circles should be precise")
+	}
+}
+
+func TestGJKOverlap(t *testing.T) {
+	if !GJKOverlap(NewRectangle(0, 0, 2, 2), NewCircle(NewVector(2.5, 1), 1)) {
+		t.Error("circle reaching into the rectangle should overlap")
+	}
+	if GJKOverlap(NewRectangle(0, 0, 2, 2), NewCircle(NewVector(3, 3), 1)) {
+		t.Error("circle off the corner should not overlap")
+	}
+	box := &TransformedShape{NewRectangle(-1, -1, 2, 2), TranslationMatrix(3, 0).Multiply(RotationMatrix(math.Pi / 4))}
+	if !GJKOverlap(box, NewRectangle(0, -1, 1.6, 2)) {
+		t.Error("rotated box corner should reach the rectangle")
+	}
+	if GJKOverlap(box, NewRectangle(0, -1, 1.5, 2)) {
+		t.Error("rotated box corner should stop short of the rectangle")
+	}
+}
+
+func TestEPA(t *testing.T) {
+	a := NewRectangle(0, 0, 2, 2)
+	b := NewRectangle(1.5, 0.5, 2, 1)
+	m := EPA(a, b)
+	if nil == m || !m.Normal.CloseEnough(NewVector(1, 0), 0.0001) || math.Abs(m.Depth-0.5) > 1e-9 {
+		t.Error("epa should match the separating axis result")
+	}
+
+	c1 := NewCircle(NewVector(0, 0), 2)
+	c2 := NewCircle(NewVector(0, 3), 2)
+	m = EPA(c1, c2)
+	if nil == m || !m.Normal.CloseEnough(NewVector(0, 1), 0.01) || math.Abs(m.Depth-1) > 0.01 {
+		t.Error("epa should find the overlap between circles")
+	}
+	if nil != EPA(c1, NewCircle(NewVector(10, 0), 1)) {
+		t.Error("separated shapes should have no penetration")
+	}
+}
+
+func TestEPADegeneratePolytope(t *testing.T) {
+	p := gjkVertex{NewVector(1, 1), NewVector(1, 1), NewVector(0, 0)}
+	if _, _, _, ok := epaClosestEdge([]gjkVertex{p, p, p}); ok {
+		t.Error("polytope with only degenerate edges should have no closest edge")
+	}
+}
@@ -0,0 +1,134 @@
+package geo2
+
+import "math"
+
+// NewHomography computes the projective transform which maps each of
+// the src points onto the matching dst point. Four correspondences
+// are solved exactly, while more than four are fitted in the least
+// squares sense using the normalized direct linear transform (DLT).
+//
+// Returns nil if fewer than four correspondences are given, the
+// slices differ in length or the points are degenerate (eg: three
+// or more of the four points are collinear)
+func NewHomography(src, dst []*Vector) *Matrix3 {
+	if len(src) < 4 || len(src) != len(dst) {
+		return nil
+	}
+
+	srcNorm, _ := normalizingMatrix(src)
+	dstNorm, dstInverse := normalizingMatrix(dst)
+	if srcNorm == nil || dstNorm == nil {
+		return nil
+	}
+
+	// build the normal equations (AᵀA) of the DLT system, the
+	// homography is the eigenvector of the smallest eigenvalue
+	ata := make([][]float64, 9)
+	for i := range ata {
+		ata[i] = make([]float64, 9)
+	}
+	for i := range src {
+		s := src[i].Clone().MultiplyMatrix(srcNorm)
+		d := dst[i].Clone().MultiplyMatrix(dstNorm)
+		rows := [2][9]float64{
+			{-s.X, -s.Y, -1, 0, 0, 0, d.X * s.X, d.X * s.Y, d.X},
+			{0, 0, 0, -s.X, -s.Y, -1, d.Y * s.X, d.Y * s.Y, d.Y},
+		}
+		for _, row := range rows {
+			for j := 0; j < 9; j++ {
+				for k := 0; k < 9; k++ {
+					ata[j][k] += row[j] * row[k]
+				}
+			}
+		}
+	}
+
+	values, vectors := jacobiEigen(ata)
+	// a unique solution requires the null space to be one
+	// dimensional, so the second smallest value must be non-zero
+	if values[7] <= 1e-10*values[0] {
+		return nil
+	}
+	h := vectors[8]
+	normalized := Matrix3{
+		{h[0], h[1], h[2]},
+		{h[3], h[4], h[5]},
+		{h[6], h[7], h[8]},
+	}
+
+	result := dstInverse.Multiply(&normalized).Multiply(srcNorm)
+	if result.GetDeterminant() == 0 {
+		return nil
+	}
+	if w := result[2][2]; w != 0 {
+		for row := 0; row < 3; row++ {
+			for col := 0; col < 3; col++ {
+				result[row][col] /= w
+			}
+		}
+	}
+	return result
+}
+
+// NewHomographyFromRectangle computes the projective transform which
+// maps the corners of the given rectangle onto the four corners of
+// the given quad (in the same order as Rectangle.ToPath). The inverse
+// of this matrix can be used to rectify the quad back to the rectangle
+func NewHomographyFromRectangle(rect *Rectangle, quad *Path) *Matrix3 {
+	if len(*quad) != 4 {
+		return nil
+	}
+	return NewHomography(*rect.ToPath(), *quad)
+}
+
+// normalizingMatrix returns the similarity transform which moves the
+// centroid of the given points to the origin and scales them to an
+// average distance of sqrt(2) (Hartley normalization), along with its
+// inverse. Both are nil if the points are all at the same position
+func normalizingMatrix(points []*Vector) (*Matrix3, *Matrix3) {
+	center := NewVector(0, 0)
+	for _, p := range points {
+		center.Add(p)
+	}
+	center.DivideScalar(float64(len(points)))
+
+	dist := 0.0
+	for _, p := range points {
+		dist += p.Clone().Sub(center).Length()
+	}
+	dist /= float64(len(points))
+	if dist == 0 {
+		return nil, nil
+	}
+
+	scale := math.Sqrt2 / dist
+	norm := &Matrix3{
+		{scale, 0, -scale * center.X},
+		{0, scale, -scale * center.Y},
+		{0, 0, 1},
+	}
+	inverse := &Matrix3{
+		{1 / scale, 0, center.X},
+		{0, 1 / scale, center.Y},
+		{0, 0, 1},
+	}
+	return norm, inverse
+}
+
+// TransformProjective applies the given matrix to every point
+// of this path in place, including the perspective divide
+// (see Vector.MultiplyMatrixProjective)
+func (path *Path) TransformProjective(m *Matrix3) {
+	for _, point := range *path {
+		point.MultiplyMatrixProjective(m)
+	}
+}
+
+// TransformProjectiveToPath returns the corners of this rectangle
+// warped by the given projective matrix (see ToPath for the
+// order of the corners)
+func (rect *Rectangle) TransformProjectiveToPath(m *Matrix3) *Path {
+	path := rect.ToPath()
+	path.TransformProjective(m)
+	return path
+}
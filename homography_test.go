@@ -0,0 +1,82 @@
+package geo2
+
+import "testing"
+
+func TestHomographyFourPoints(t *testing.T) {
+	src := []*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(1, 1), NewVector(0, 1)}
+	dst := []*Vector{NewVector(10, 10), NewVector(30, 12), NewVector(28, 25), NewVector(8, 20)}
+	h := NewHomography(src, dst)
+	if h == nil {
+		t.Fatal("homography should be found for four points")
+	}
+	for i := range src {
+		if !src[i].Clone().MultiplyMatrixProjective(h).CloseEnough(dst[i], 0.0001) {
+			t.Error("homography should map each source point onto its destination")
+		}
+	}
+}
+
+func TestHomographyLeastSquares(t *testing.T) {
+	expected := Matrix3{
+		{1.2, 0.1, 5},
+		{-0.2, 0.9, 3},
+		{0.001, 0.002, 1},
+	}
+	var src, dst []*Vector
+	for x := 0.0; x < 5; x++ {
+		for y := 0.0; y < 5; y++ {
+			p := NewVector(x*10, y*10)
+			src = append(src, p)
+			dst = append(dst, p.Clone().MultiplyMatrixProjective(&expected))
+		}
+	}
+	h := NewHomography(src, dst)
+	if h == nil || !h.CloseEnough(&expected, 0.0001) {
+		t.Error("homography should be recovered from many points")
+	}
+}
+
+func TestHomographyMapScale(t *testing.T) {
+	src := []*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(1, 1), NewVector(0, 1)}
+	dst := []*Vector{
+		NewVector(5e5, 4e6), NewVector(5e5+200, 4e6+10),
+		NewVector(5e5+190, 4e6+220), NewVector(5e5-5, 4e6+200),
+	}
+	h := NewHomography(src, dst)
+	if h == nil {
+		t.Fatal("homography should be found for distant points")
+	}
+	for i := range src {
+		if !src[i].Clone().MultiplyMatrixProjective(h).CloseEnough(dst[i], 0.01) {
+			t.Error("homography should map each source point onto its distant destination")
+		}
+	}
+}
+
+func TestHomographyDegenerate(t *testing.T) {
+	src := []*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(2, 0), NewVector(0, 1)}
+	dst := []*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(2, 0), NewVector(0, 1)}
+	if NewHomography(src, dst) != nil {
+		t.Error("collinear points should not produce a homography")
+	}
+	if NewHomography(src[:3], dst[:3]) != nil {
+		t.Error("three points should not produce a homography")
+	}
+}
+
+func TestRectangleProjectiveCorners(t *testing.T) {
+	rect := NewRectangle(0, 0, 100, 50)
+	quad := &Path{NewVector(5, 5), NewVector(90, 0), NewVector(100, 60), NewVector(0, 40)}
+	h := NewHomographyFromRectangle(rect, quad)
+	warped := rect.TransformProjectiveToPath(h)
+	for i, corner := range *warped {
+		if !corner.CloseEnough((*quad)[i], 0.0001) {
+			t.Error("rectangle corners should be warped onto the quad")
+		}
+	}
+	inverse := h.GetInverse()
+	warped.TransformProjective(inverse)
+	if !(*warped)[2].CloseEnough(NewVector(100, 50), 0.0001) {
+		t.Error("inverse homography should rectify the quad")
+	}
+}
@@ -0,0 +1,221 @@
+package geo2
+
+import "math"
+
+// ICPMethod selects the error metric minimized by ICP
+type ICPMethod int
+
+const (
+	// ICPPointToPoint matches each source point to the nearest
+	// target point and minimizes the distance between them
+	ICPPointToPoint ICPMethod = iota
+	// ICPPointToLine matches each source point to the nearest edge
+	// of the target path and minimizes the distance along the edge
+	// normal, which lets points slide along the outline and usually
+	// converges in far fewer iterations
+	ICPPointToLine
+)
+
+// ICPOptions configures an ICP registration. The zero
+// value is usable and runs point to point ICP
+type ICPOptions struct {
+	// Method is the error metric to minimize
+	Method ICPMethod
+	// MaxIterations limits the number of iterations (default 50)
+	MaxIterations int
+	// Tolerance stops the iteration once the error improves
+	// by less than this amount (default 1e-9)
+	Tolerance float64
+	// MaxDistance ignores correspondences which are further
+	// apart than this distance, or any distance when zero
+	MaxDistance float64
+	// Closed treats the target path as a closed loop when matching
+	// against its edges (only used by ICPPointToLine)
+	Closed bool
+	// Initial is an initial guess for the transform (default identity)
+	Initial *Matrix3
+}
+
+// ICPResult is the outcome of an ICP registration
+type ICPResult struct {
+	// Transform maps the source points onto the target
+	Transform *Matrix3
+	// Iterations is the number of iterations which were run
+	Iterations int
+	// Error is the RMS distance of the final correspondences
+	Error float64
+	// Converged is true if the tolerance was reached
+	// before the maximum number of iterations
+	Converged bool
+}
+
+// ICP registers the source points to the target path using iterative
+// closest point, returning the rigid transform which maps source onto
+// target. Returns nil if either set is empty or no correspondences
+// fall within the maximum distance
+func ICP(source, target *Path, opts *ICPOptions) *ICPResult {
+	if nil == opts {
+		opts = &ICPOptions{}
+	}
+	if 0 == len(*source) || 0 == len(*target) {
+		return nil
+	}
+	maxIterations := opts.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = 50
+	}
+	tolerance := opts.Tolerance
+	if tolerance <= 0 {
+		tolerance = 1e-9
+	}
+
+	var edges []*Line
+	if ICPPointToLine == opts.Method {
+		edges = pathEdges(target, opts.Closed)
+	}
+
+	transform := IdentityMatrix()
+	if nil != opts.Initial {
+		transform.Copy(opts.Initial)
+	}
+	result := &ICPResult{Transform: transform, Error: math.Inf(1)}
+	for result.Iterations < maxIterations {
+		moved := source.Transformed(transform).(*Path)
+		var step *Matrix3
+		var err float64
+		if nil == edges {
+			step, err = icpPointStep(*moved, *target, opts.MaxDistance)
+		} else {
+			step, err = icpLineStep(*moved, edges, opts.MaxDistance)
+		}
+		if nil == step {
+			break
+		}
+		result.Iterations++
+		transform = step.Multiply(transform)
+		improvement := result.Error - err
+		result.Error = err
+		if improvement >= 0 && improvement < tolerance {
+			result.Converged = true
+			break
+		}
+	}
+	if 0 == result.Iterations {
+		return nil
+	}
+
+	// report the error of the final transform rather
+	// than the error measured before the last step
+	moved := source.Transformed(transform).(*Path)
+	if nil == edges {
+		_, result.Error = icpPointStep(*moved, *target, opts.MaxDistance)
+	} else {
+		_, result.Error = icpLineStep(*moved, edges, opts.MaxDistance)
+	}
+	result.Transform = transform
+	return result
+}
+
+// icpPointStep matches each point to its closest target point and
+// returns the rigid transform minimizing those distances along with
+// the RMS distance of the matches before the transform is applied
+func icpPointStep(points, target []*Vector, maxDistance float64) (*Matrix3, float64) {
+	var src, dst []*Vector
+	sum := 0.0
+	for _, p := range points {
+		best := math.Inf(1)
+		var match *Vector
+		for _, q := range target {
+			if d := p.Clone().Sub(q).LengthSqd(); d < best {
+				best, match = d, q
+			}
+		}
+		if maxDistance > 0 && best > maxDistance*maxDistance {
+			continue
+		}
+		src = append(src, p)
+		dst = append(dst, match)
+		sum += best
+	}
+	if 0 == len(src) {
+		return nil, 0
+	}
+	err := math.Sqrt(sum / float64(len(src)))
+	if 1 == len(src) {
+		return TranslationMatrix(dst[0].X-src[0].X, dst[0].Y-src[0].Y), err
+	}
+	m, _ := FitRigid(src, dst, nil)
+	return m, err
+}
+
+// icpLineStep matches each point to its closest target edge and solves
+// the linearized point to line problem for a small rotation and
+// translation, returning that transform along with the RMS distance
+// of the matches before the transform is applied
+func icpLineStep(points []*Vector, edges []*Line, maxDistance float64) (*Matrix3, float64) {
+	ata := [][]float64{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}
+	atb := []float64{0, 0, 0}
+	count := 0
+	sum := 0.0
+	for _, p := range points {
+		best := math.Inf(1)
+		var match *Vector
+		var edge *Line
+		for _, e := range edges {
+			q := e.ClosestPoint(p, true)
+			if d := q.Clone().Sub(p).LengthSqd(); d < best {
+				best, match, edge = d, q, e
+			}
+		}
+		if maxDistance > 0 && best > maxDistance*maxDistance {
+			continue
+		}
+		normal := edge.ToVector()
+		if 0 == normal.LengthSqd() {
+			// degenerate edge, fall back to the direction of the point
+			normal = p.Clone().Sub(match)
+			if 0 == normal.LengthSqd() {
+				continue
+			}
+		}
+		normal.Set(-normal.Y, normal.X).Normalize()
+		row := [3]float64{p.Cross(normal), normal.X, normal.Y}
+		b := -p.Clone().Sub(match).Dot(normal)
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				ata[j][k] += row[j] * row[k]
+			}
+			atb[j] += row[j] * b
+		}
+		count++
+		sum += best
+	}
+	if 0 == count {
+		return nil, 0
+	}
+	err := math.Sqrt(sum / float64(count))
+	x := solveLinear(ata, atb, 1e-12)
+	if nil == x {
+		// the problem is under constrained (eg: all points on a single
+		// straight edge), so settle for the translation along the normals
+		x = []float64{0, 0, 0}
+	}
+	return TranslationMatrix(x[1], x[2]).Multiply(RotationMatrix(x[0])), err
+}
+
+// pathEdges returns the edges between the consecutive points of
+// the given path, including the closing edge if closed is true
+func pathEdges(path *Path, closed bool) []*Line {
+	points := *path
+	if 1 == len(points) {
+		return []*Line{NewLine(points[0], points[0])}
+	}
+	edges := make([]*Line, 0, len(points))
+	for i := 0; i+1 < len(points); i++ {
+		edges = append(edges, NewLine(points[i], points[i+1]))
+	}
+	if closed && len(points) > 2 {
+		edges = append(edges, NewLine(points[len(points)-1], points[0]))
+	}
+	return edges
+}
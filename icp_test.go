@@ -0,0 +1,70 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+// icpOutline returns a densely sampled, asymmetric closed outline
+func icpOutline() *Path {
+	corners := []*Vector{
+		NewVector(0, 0), NewVector(10, 0), NewVector(10, 4),
+		NewVector(4, 4), NewVector(4, 8), NewVector(0, 8),
+	}
+	path := &Path{}
+	for i, a := range corners {
+		b := corners[(i+1)%len(corners)]
+		for j := 0; j < 10; j++ {
+			path.Append(NewLine(a, b).GetPosition(float64(j) / 10))
+		}
+	}
+	return path
+}
+
+func TestICPPointToPoint(t *testing.T) {
+	target := icpOutline()
+	expected := TranslationMatrix(0.4, -0.3).Multiply(RotationMatrix(0.05))
+	source := target.Transformed(expected.GetInverse()).(*Path)
+
+	result := ICP(source, target, &ICPOptions{MaxIterations: 100})
+	if nil == result || !result.Transform.CloseEnough(expected, 0.001) {
+		t.Error("point to point icp should recover the transform")
+	}
+	if result.Error > 1e-6 {
+		t.Error("registered points should coincide with the target")
+	}
+}
+
+func TestICPPointToLine(t *testing.T) {
+	target := icpOutline()
+	expected := TranslationMatrix(-0.5, 0.6).Multiply(RotationMatrix(-0.08))
+	// sample the source away from the target vertices so
+	// that only the edges can be matched exactly
+	source := &Path{}
+	for i := range *target {
+		a := (*target)[i]
+		b := (*target)[(i+1)%len(*target)]
+		source.Append(NewLine(a, b).GetPosition(0.37))
+	}
+	source.Transform(expected.GetInverse())
+
+	result := ICP(source, target, &ICPOptions{Method: ICPPointToLine, Closed: true})
+	if nil == result || !result.Transform.CloseEnough(expected, 0.001) {
+		t.Error("point to line icp should recover the transform")
+	}
+	if !result.Converged || result.Error > 1e-6 {
+		t.Error("point to line icp should converge")
+	}
+}
+
+func TestICPMaxDistance(t *testing.T) {
+	target := &Path{NewVector(0, 0), NewVector(1, 0)}
+	source := &Path{NewVector(100, 100)}
+	if nil != ICP(source, target, &ICPOptions{MaxDistance: 1}) {
+		t.Error("icp should fail without correspondences in range")
+	}
+	result := ICP(source, target, nil)
+	if nil == result || math.Abs(result.Error) > 1e-9 {
+		t.Error("single point should be translated onto its match")
+	}
+}
@@ -0,0 +1,217 @@
+package geo2
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// KDPoint is a point stored in a KDTree
+type KDPoint struct {
+	Point *Vector
+	// Payload is the value given for this point when
+	// the tree was built, or nil if there was none
+	Payload interface{}
+	// Index is the position of this point in the
+	// slice the tree was built from
+	Index int
+}
+
+// KDTree is a static 2d tree over a set of points for fast
+// nearest neighbor and range queries. The points are referenced
+// rather than copied, and must not be moved once the tree is built
+type KDTree struct {
+	// points are stored as an implicit balanced tree, where the
+	// root of any range is its middle element, split along x at
+	// even depths and along y at odd depths
+	points []*KDPoint
+}
+
+// NewKDTree builds a tree over the given points in O(n log n)
+// expected time. Payloads may be nil, otherwise it must be the
+// same length as points and each payload is attached to the
+// matching point
+func NewKDTree(points []*Vector, payloads []interface{}) *KDTree {
+	tree := &KDTree{points: make([]*KDPoint, len(points))}
+	for i, p := range points {
+		kp := &KDPoint{Point: p, Index: i}
+		if nil != payloads {
+			kp.Payload = payloads[i]
+		}
+		tree.points[i] = kp
+	}
+	tree.build(0, len(points), 0)
+	return tree
+}
+
+// build arranges the given range of points into a subtree
+func (tree *KDTree) build(lo, hi, depth int) {
+	if hi-lo <= 1 {
+		return
+	}
+	mid := (lo + hi) / 2
+	kdSelect(tree.points[lo:hi], mid-lo, depth%2)
+	tree.build(lo, mid, depth+1)
+	tree.build(mid+1, hi, depth+1)
+}
+
+// kdSelect partially sorts the given points along the given axis so
+// that the kth point is in its sorted position, with no larger points
+// before it and no smaller points after it (quickselect)
+func kdSelect(points []*KDPoint, k, axis int) {
+	lo, hi := 0, len(points)-1
+	for lo < hi {
+		// median of three pivot to avoid the worst case on sorted input
+		mid := (lo + hi) / 2
+		if kdAxis(points[mid].Point, axis) < kdAxis(points[lo].Point, axis) {
+			points[lo], points[mid] = points[mid], points[lo]
+		}
+		if kdAxis(points[hi].Point, axis) < kdAxis(points[lo].Point, axis) {
+			points[lo], points[hi] = points[hi], points[lo]
+		}
+		if kdAxis(points[hi].Point, axis) < kdAxis(points[mid].Point, axis) {
+			points[mid], points[hi] = points[hi], points[mid]
+		}
+		pivot := kdAxis(points[mid].Point, axis)
+		i, j := lo, hi
+		for i <= j {
+			for kdAxis(points[i].Point, axis) < pivot {
+				i++
+			}
+			for kdAxis(points[j].Point, axis) > pivot {
+				j--
+			}
+			if i <= j {
+				points[i], points[j] = points[j], points[i]
+				i++
+				j--
+			}
+		}
+		if k <= j {
+			hi = j
+		} else if k >= i {
+			lo = i
+		} else {
+			return
+		}
+	}
+}
+
+func kdAxis(v *Vector, axis int) float64 {
+	if 0 == axis {
+		return v.X
+	}
+	return v.Y
+}
+
+// Len returns the number of points in this tree
+func (tree *KDTree) Len() int {
+	return len(tree.points)
+}
+
+// Nearest returns the point closest to the given
+// point, or nil if the tree is empty
+func (tree *KDTree) Nearest(vec *Vector) *KDPoint {
+	result := tree.KNearest(vec, 1)
+	if 0 == len(result) {
+		return nil
+	}
+	return result[0]
+}
+
+// KNearest returns up to k points closest to the
+// given point, ordered from closest to furthest
+func (tree *KDTree) KNearest(vec *Vector, k int) []*KDPoint {
+	if k <= 0 {
+		return nil
+	}
+	found := &priorityQueue{}
+	tree.nearest(vec, k, 0, len(tree.points), 0, found)
+	result := make([]*KDPoint, found.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(found).(priorityItem).value.(*KDPoint)
+	}
+	return result
+}
+
+// nearest searches the given range of points, keeping the k closest
+// points found so far in the given heap. They are queued by their
+// negated squared distance, which keeps the furthest on top
+func (tree *KDTree) nearest(vec *Vector, k, lo, hi, depth int, found *priorityQueue) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	p := tree.points[mid]
+	d := p.Point.Clone().Sub(vec).LengthSqd()
+	if found.Len() < k {
+		heap.Push(found, priorityItem{-d, p})
+	} else if d < -(*found)[0].priority {
+		(*found)[0] = priorityItem{-d, p}
+		heap.Fix(found, 0)
+	}
+
+	diff := kdAxis(vec, depth%2) - kdAxis(p.Point, depth%2)
+	nearLo, nearHi, farLo, farHi := lo, mid, mid+1, hi
+	if diff > 0 {
+		nearLo, nearHi, farLo, farHi = mid+1, hi, lo, mid
+	}
+	tree.nearest(vec, k, nearLo, nearHi, depth+1, found)
+	if found.Len() < k || diff*diff < -(*found)[0].priority {
+		tree.nearest(vec, k, farLo, farHi, depth+1, found)
+	}
+}
+
+// WithinRadius returns every point within the given distance of the
+// given point (inclusive), ordered from closest to furthest
+func (tree *KDTree) WithinRadius(vec *Vector, radius float64) []*KDPoint {
+	area := NewRectangle(vec.X-radius, vec.Y-radius, radius*2, radius*2)
+	var candidates []priorityItem
+	limit := radius * radius
+	tree.within(area, 0, len(tree.points), 0, func(p *KDPoint) {
+		if d := p.Point.Clone().Sub(vec).LengthSqd(); d <= limit {
+			candidates = append(candidates, priorityItem{d, p})
+		}
+	})
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].priority < candidates[j].priority
+	})
+	result := make([]*KDPoint, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.value.(*KDPoint)
+	}
+	return result
+}
+
+// WithinRectangle returns every point inside
+// (or on the edge of) the given rectangle
+func (tree *KDTree) WithinRectangle(rect *Rectangle) []*KDPoint {
+	var result []*KDPoint
+	tree.within(rect.Clone().Normalize(), 0, len(tree.points), 0, func(p *KDPoint) {
+		result = append(result, p)
+	})
+	return result
+}
+
+// within calls visit for each point in the given range which
+// is contained by the given area
+func (tree *KDTree) within(area *Rectangle, lo, hi, depth int, visit func(*KDPoint)) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	p := tree.points[mid]
+	if area.Contains(p.Point) {
+		visit(p)
+	}
+	min, max := area.X, area.Right()
+	if 1 == depth%2 {
+		min, max = area.Y, area.Bottom()
+	}
+	split := kdAxis(p.Point, depth%2)
+	if min <= split {
+		tree.within(area, lo, mid, depth+1, visit)
+	}
+	if max >= split {
+		tree.within(area, mid+1, hi, depth+1, visit)
+	}
+}
@@ -0,0 +1,104 @@
+package geo2
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomPoints(seed int64, count int) []*Vector {
+	rng := rand.New(rand.NewSource(seed))
+	points := make([]*Vector, count)
+	for i := range points {
+		points[i] = NewVector(rng.Float64()*200-100, rng.Float64()*200-100)
+	}
+	return points
+}
+
+func TestKDTreeNearest(t *testing.T) {
+	points := randomPoints(8, 1000)
+	payloads := make([]interface{}, len(points))
+	for i := range payloads {
+		payloads[i] = i * 10
+	}
+	tree := NewKDTree(points, payloads)
+	for _, query := range randomPoints(9, 50) {
+		best := 0
+		for i, p := range points {
+			if p.Clone().Sub(query).LengthSqd() < points[best].Clone().Sub(query).LengthSqd() {
+				best = i
+			}
+		}
+		nearest := tree.Nearest(query)
+		if nearest.Index != best || nearest.Point != points[best] || nearest.Payload != best*10 {
+			t.Error("nearest should match a linear scan")
+		}
+	}
+	if nil != NewKDTree(nil, nil).Nearest(NewVector(0, 0)) {
+		t.Error("empty tree should have no nearest point")
+	}
+}
+
+func TestKDTreeKNearest(t *testing.T) {
+	points := randomPoints(10, 500)
+	tree := NewKDTree(points, nil)
+	query := NewVector(5, -5)
+	result := tree.KNearest(query, 8)
+	if len(result) != 8 {
+		t.Fatal("k nearest should return k points")
+	}
+	furthest := result[7].Point.Clone().Sub(query).LengthSqd()
+	closer := 0
+	for _, p := range points {
+		if p.Clone().Sub(query).LengthSqd() <= furthest {
+			closer++
+		}
+	}
+	if closer != 8 {
+		t.Error("k nearest should find the closest points")
+	}
+	for i := 1; i < len(result); i++ {
+		if result[i].Point.Clone().Sub(query).LengthSqd() < result[i-1].Point.Clone().Sub(query).LengthSqd() {
+			t.Error("k nearest should be ordered by distance")
+		}
+	}
+	if len(NewKDTree(points[:3], nil).KNearest(query, 5)) != 3 {
+		t.Error("k nearest should be limited to the number of points")
+	}
+}
+
+func TestKDTreeRanges(t *testing.T) {
+	points := randomPoints(11, 800)
+	// duplicate points should not confuse the partitioning
+	points = append(points, points[0].Clone(), points[0].Clone())
+	tree := NewKDTree(points, nil)
+
+	center := NewVector(-20, 30)
+	var expected []int
+	for i, p := range points {
+		if p.Clone().Sub(center).Length() <= 25 {
+			expected = append(expected, i)
+		}
+	}
+	var found []interface{}
+	for _, p := range tree.WithinRadius(center, 25) {
+		found = append(found, p.Index)
+	}
+	if !equalInts(sortedInts(found), expected) {
+		t.Error("radius query should match a linear scan")
+	}
+
+	rect := NewRectangle(10, -40, 35, 60)
+	expected = nil
+	for i, p := range points {
+		if rect.Contains(p) {
+			expected = append(expected, i)
+		}
+	}
+	found = nil
+	for _, p := range tree.WithinRectangle(rect) {
+		found = append(found, p.Index)
+	}
+	if !equalInts(sortedInts(found), expected) {
+		t.Error("rectangle query should match a linear scan")
+	}
+}
@@ -0,0 +1,134 @@
+package geo2
+
+import "math"
+
+// solveLinear solves the square system a*x = b using gaussian
+// elimination with partial pivoting. Neither a nor b are modified.
+// Returns nil if any pivot is at or below tolerance relative to
+// the largest element of a (or exactly zero for a tolerance of 0)
+func solveLinear(a [][]float64, b []float64, tolerance float64) []float64 {
+	n := len(b)
+	m := make([][]float64, n)
+	scale := 0.0
+	for i := range m {
+		m[i] = make([]float64, n+1)
+		copy(m[i], a[i])
+		m[i][n] = b[i]
+		for j := 0; j < n; j++ {
+			scale = math.Max(scale, math.Abs(a[i][j]))
+		}
+	}
+	if scale == 0 {
+		return nil
+	}
+	tolerance *= scale * float64(n)
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(m[pivot][col]) <= tolerance || 0 == m[pivot][col] {
+			return nil
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		for row := col + 1; row < n; row++ {
+			f := m[row][col] / m[col][col]
+			if f == 0 {
+				continue
+			}
+			for k := col; k <= n; k++ {
+				m[row][k] -= f * m[col][k]
+			}
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := m[row][n]
+		for k := row + 1; k < n; k++ {
+			sum -= m[row][k] * x[k]
+		}
+		x[row] = sum / m[row][row]
+	}
+	return x
+}
+
+// jacobiEigen computes the eigenvalues and eigenvectors of the
+// given symmetric matrix using cyclic jacobi rotations. The input
+// is not modified. Eigenvalues are returned in descending order and
+// vectors[i] is the unit eigenvector belonging to values[i]
+func jacobiEigen(a [][]float64) (values []float64, vectors [][]float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	v := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		copy(m[i], a[i])
+		v[i] = make([]float64, n)
+		v[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p] = c*mkp - s*mkq
+					m[k][q] = s*mkp + c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k] = c*mpk - s*mqk
+					m[q][k] = s*mpk + c*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	for i := 1; i < n; i++ {
+		for j := i; j > 0 && m[order[j]][order[j]] > m[order[j-1]][order[j-1]]; j-- {
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+	values = make([]float64, n)
+	vectors = make([][]float64, n)
+	for i, idx := range order {
+		values[i] = m[idx][idx]
+		vectors[i] = make([]float64, n)
+		for k := 0; k < n; k++ {
+			vectors[i][k] = v[k][idx]
+		}
+	}
+	return values, vectors
+}
@@ -14,6 +14,26 @@ func NewLine(pointA, pointB *Vector) *Line {
 	return &Line{pointA, pointB}
 }
 
+// Clone creates a copy of this line by value
+func (line *Line) Clone() *Line {
+	return NewLine(line.A.Clone(), line.B.Clone())
+}
+
+// Transform applies the given matrix to both
+// points of this line in place
+func (line *Line) Transform(m *Matrix3) {
+	line.A.MultiplyMatrix(m)
+	line.B.MultiplyMatrix(m)
+}
+
+// Transformed returns a copy of this line
+// transformed by the given matrix
+func (line *Line) Transformed(m *Matrix3) Transformable {
+	clone := line.Clone()
+	clone.Transform(m)
+	return clone
+}
+
 //ToVector returns the vector of line.A -> line.B
 func (line *Line) ToVector() *Vector {
 	return line.B.Clone().Sub(line.A)
@@ -52,7 +72,7 @@ func (line *Line) GetPerc(point *Vector) float64 {
 //with the line going from line line to a given point
 //to figure out which side of the line the point is on
 func (line *Line) CrossWithPoint(v *Vector) float64 {
-	return line.ToVector().Cross(v.Clone().Sub(line.A))
+	return orient(line.A, line.B, v)
 }
 
 // DistanceToPoint returns the distance from this line to the
@@ -70,9 +90,13 @@ func (line *Line) DistanceToPoint(point *Vector, clamp bool) float64 {
 // defined by A and B
 func (line *Line) ClosestPoint(point *Vector, clamp bool) *Vector {
 	ap := point.Clone().Sub(line.A)
-	ab := point.Clone().Sub(line.B)
+	ab := line.ToVector()
 
-	var perc = (ap.X*ab.X + ap.Y*ab.Y) / ab.LengthSqd()
+	lengthSqd := ab.LengthSqd()
+	if 0 == lengthSqd {
+		return line.A.Clone()
+	}
+	var perc = ap.Dot(ab) / lengthSqd
 
 	if clamp {
 		perc = math.Min(1, math.Max(0, perc))
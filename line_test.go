@@ -27,3 +27,17 @@ func ExampleIntersection() {
 	// Output:
 	// {x: 1.0000, y: 1.0000}
 }
+
+func ExampleLine_ClosestPoint() {
+	line := &Line{
+		&Vector{0, 0},
+		&Vector{4, 0},
+	}
+	fmt.Println(line.ClosestPoint(&Vector{1, 3}, true))
+	fmt.Println(line.ClosestPoint(&Vector{6, 3}, true))
+	fmt.Println(line.DistanceToPoint(&Vector{6, 3}, false))
+	// Output:
+	// {x: 1.0000, y: 0.0000}
+	// {x: 4.0000, y: 0.0000}
+	// 3
+}
@@ -77,31 +77,11 @@ func (pm *Matrix3) Multiply(prhs *Matrix3) *Matrix3 {
   return &lhs
 }
 
-// GetInverse gets the inverse matrix or returns nil if no inverse
+// GetInverse gets the inverse matrix or returns nil if there is
+// no inverse, or the matrix is too close to singular to be inverted
+// reliably (see GetInverseTolerance and DefaultSingularTolerance)
 func (pm *Matrix3) GetInverse() *Matrix3 {
-  det := pm.GetDeterminant()
-
-  if det == 0.0 {
-    return nil
-  }
-
-  trans := (*pm.GetTranspose())
-  adj := (*pm.GetAdjoint())
-
-  frac := 1.0 / det
-
-  //reuse transpose to save memory
-  trans[0][0] = adj[0][0] * frac
-  trans[0][1] = adj[0][1] * frac
-  trans[0][2] = adj[0][2] * frac
-  trans[1][0] = adj[1][0] * frac
-  trans[1][1] = adj[1][1] * frac
-  trans[1][2] = adj[1][2] * frac
-  trans[2][0] = adj[2][0] * frac
-  trans[2][1] = adj[2][1] * frac
-  trans[2][2] = adj[2][2] * frac
-
-  return &trans
+  return pm.GetInverseTolerance(DefaultSingularTolerance)
 }
 
 // GetTranspose returns the transpose matrix
@@ -225,3 +205,26 @@ func (pm *Matrix3) CloseEnough(prhs *Matrix3, sigDigits float64) bool {
   }
   return true
 }
+
+// TranslationMatrix creates a new Matrix3 which
+// translates by the given x and y values
+func TranslationMatrix(x, y float64) *Matrix3 {
+  m := Matrix3{{1, 0, x}, {0, 1, y}, {0, 0, 1}}
+  return &m
+}
+
+// RotationMatrix creates a new Matrix3 which rotates
+// about the origin by the given angle (in radians)
+func RotationMatrix(angle float64) *Matrix3 {
+  c := math.Cos(angle)
+  s := math.Sin(angle)
+  m := Matrix3{{c, -s, 0}, {s, c, 0}, {0, 0, 1}}
+  return &m
+}
+
+// ScaleMatrix creates a new Matrix3 which scales
+// about the origin by the given x and y factors
+func ScaleMatrix(x, y float64) *Matrix3 {
+  m := Matrix3{{x, 0, 0}, {0, y, 0}, {0, 0, 1}}
+  return &m
+}
@@ -0,0 +1,275 @@
+package geo2
+
+import "math"
+
+// DefaultSingularTolerance is the reciprocal condition number below
+// which GetInverse considers a matrix to be singular
+const DefaultSingularTolerance = 1e-12
+
+// SVD computes the singular value decomposition of this matrix such
+// that m = u * diag(s) * vᵀ, where u and v are orthogonal and the
+// singular values in s are non-negative and in descending order
+func (pm *Matrix3) SVD() (u *Matrix3, s [3]float64, v *Matrix3) {
+	// one sided jacobi: rotate pairs of columns of a until they are
+	// all orthogonal, accumulating the rotations into v
+	a := *pm
+	vm := *IdentityMatrix()
+	for sweep := 0; sweep < 60; sweep++ {
+		rotated := false
+		for p := 0; p < 2; p++ {
+			for q := p + 1; q < 3; q++ {
+				var alpha, beta, gamma float64
+				for k := 0; k < 3; k++ {
+					alpha += a[k][p] * a[k][p]
+					beta += a[k][q] * a[k][q]
+					gamma += a[k][p] * a[k][q]
+				}
+				if gamma == 0 || math.Abs(gamma) <= 1e-15*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				zeta := (beta - alpha) / (2 * gamma)
+				t := 1 / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				sn := c * t
+				for k := 0; k < 3; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - sn*akq
+					a[k][q] = sn*akp + c*akq
+					vkp, vkq := vm[k][p], vm[k][q]
+					vm[k][p] = c*vkp - sn*vkq
+					vm[k][q] = sn*vkp + c*vkq
+				}
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	// the singular values are the lengths of the resulting columns
+	order := [3]int{0, 1, 2}
+	var norms [3]float64
+	for j := 0; j < 3; j++ {
+		norms[j] = math.Sqrt(a[0][j]*a[0][j] + a[1][j]*a[1][j] + a[2][j]*a[2][j])
+	}
+	for i := 1; i < 3; i++ {
+		for j := i; j > 0 && norms[order[j]] > norms[order[j-1]]; j-- {
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+
+	u = NewMatrix()
+	v = NewMatrix()
+	var cols [3]*[3]float64
+	valid := 0
+	for i, j := range order {
+		s[i] = norms[j]
+		for k := 0; k < 3; k++ {
+			v[k][i] = vm[k][j]
+		}
+		col := [3]float64{}
+		if s[i] > 1e-300 && s[i] > norms[order[0]]*1e-15 {
+			for k := 0; k < 3; k++ {
+				col[k] = a[k][j] / s[i]
+			}
+			valid++
+		} else {
+			s[i] = 0
+		}
+		cols[i] = &col
+	}
+
+	// complete the basis of u for any zero singular values
+	switch valid {
+	case 0:
+		cols[0] = &[3]float64{1, 0, 0}
+		fallthrough
+	case 1:
+		c0 := cols[0]
+		// pick the axis least aligned with the first column
+		axis := [3]float64{1, 0, 0}
+		if math.Abs(c0[0]) > math.Abs(c0[1]) {
+			axis = [3]float64{0, 1, 0}
+		}
+		c1 := cross3(c0, &axis)
+		normalize3(&c1)
+		cols[1] = &c1
+		fallthrough
+	case 2:
+		c2 := cross3(cols[0], cols[1])
+		normalize3(&c2)
+		cols[2] = &c2
+	}
+	for i := 0; i < 3; i++ {
+		for k := 0; k < 3; k++ {
+			u[k][i] = cols[i][k]
+		}
+	}
+	return u, s, v
+}
+
+// SVD2 computes the singular value decomposition of the upper left
+// 2x2 (linear) part of this matrix such that it equals
+// u * diag(s) * vᵀ. The returned u and v are 2x2 rotations or
+// reflections embedded in a Matrix3 with a 1 in the bottom right
+func (pm *Matrix3) SVD2() (u *Matrix3, s [2]float64, v *Matrix3) {
+	m := *pm
+	e := (m[0][0] + m[1][1]) / 2
+	f := (m[0][0] - m[1][1]) / 2
+	g := (m[1][0] + m[0][1]) / 2
+	h := (m[1][0] - m[0][1]) / 2
+	q := math.Hypot(e, h)
+	r := math.Hypot(f, g)
+	a1 := math.Atan2(g, f)
+	a2 := math.Atan2(h, e)
+	theta := (a2 - a1) / 2
+	phi := (a2 + a1) / 2
+
+	s = [2]float64{q + r, q - r}
+	u = RotationMatrix(phi)
+	v = RotationMatrix(-theta)
+	if s[1] < 0 {
+		s[1] = -s[1]
+		v[0][1] = -v[0][1]
+		v[1][1] = -v[1][1]
+	}
+	return u, s, v
+}
+
+// SymmetricEigen computes the eigen decomposition of this matrix,
+// which is assumed to be symmetric, such that m = q * diag(values) * qᵀ.
+// The eigenvalues are in descending order and the columns of q are the
+// matching unit eigenvectors
+func (pm *Matrix3) SymmetricEigen() (values [3]float64, q *Matrix3) {
+	m := *pm
+	vals, vecs := jacobiEigen([][]float64{m[0][:], m[1][:], m[2][:]})
+	q = NewMatrix()
+	for i := 0; i < 3; i++ {
+		values[i] = vals[i]
+		for k := 0; k < 3; k++ {
+			q[k][i] = vecs[i][k]
+		}
+	}
+	return values, q
+}
+
+// PolarDecomposition splits this matrix into an orthogonal matrix r
+// and a symmetric positive semi-definite matrix p such that m = r * p.
+// For a transform this separates the rotation from the scale and
+// shear. If the matrix contains a reflection r will include it
+func (pm *Matrix3) PolarDecomposition() (r, p *Matrix3) {
+	u, s, v := pm.SVD()
+	vt := v.GetTranspose()
+	r = u.Multiply(vt)
+	diag := Matrix3{{s[0], 0, 0}, {0, s[1], 0}, {0, 0, s[2]}}
+	p = v.Multiply(&diag).Multiply(vt)
+	return r, p
+}
+
+// ConditionNumber returns the ratio of the largest to the smallest
+// singular value of this matrix. Large values indicate that the matrix
+// is close to singular and that solving or inverting it will lose
+// precision. Returns +Inf for singular matrices
+func (pm *Matrix3) ConditionNumber() float64 {
+	_, s, _ := pm.SVD()
+	if s[2] == 0 {
+		return math.Inf(1)
+	}
+	return s[0] / s[2]
+}
+
+// IsSingular returns true if the reciprocal condition number
+// of this matrix is at or below the given tolerance
+func (pm *Matrix3) IsSingular(tolerance float64) bool {
+	_, s, _ := pm.SVD()
+	return s[0] == 0 || s[2]/s[0] <= tolerance
+}
+
+// Solve solves the linear system m * x = b using gaussian elimination
+// with partial pivoting. Returns false if the matrix is exactly
+// singular (see IsSingular to detect near singular matrices)
+func (pm *Matrix3) Solve(b [3]float64) ([3]float64, bool) {
+	m := *pm
+	x := solveLinear([][]float64{m[0][:], m[1][:], m[2][:]}, b[:], 0)
+	if nil == x {
+		return [3]float64{}, false
+	}
+	return [3]float64{x[0], x[1], x[2]}, true
+}
+
+// GetInverseTolerance gets the inverse matrix, or returns nil if it is
+// singular or too close to singular to be inverted reliably. For affine
+// matrices this is judged by the reciprocal condition number of the
+// linear (upper left 2x2) part, so that the size of the translation
+// does not matter. Other matrices are first balanced so that their
+// translation column and perspective row are of similar size
+func (pm *Matrix3) GetInverseTolerance(tolerance float64) *Matrix3 {
+	if pm.nearlySingular(tolerance) {
+		return nil
+	}
+	inv := NewMatrix()
+	for col := 0; col < 3; col++ {
+		e := [3]float64{}
+		e[col] = 1
+		x, ok := pm.Solve(e)
+		if !ok {
+			return nil
+		}
+		for row := 0; row < 3; row++ {
+			inv[row][col] = x[row]
+		}
+	}
+	return inv
+}
+
+// nearlySingular returns true if the reciprocal condition number of
+// this matrix, ignoring the scale of its translation, is at or below
+// the given tolerance (see GetInverseTolerance)
+func (pm *Matrix3) nearlySingular(tolerance float64) bool {
+	m := *pm
+	if m[2][0] == 0 && m[2][1] == 0 && m[2][2] == 1 {
+		_, s, _ := pm.SVD2()
+		return s[0] == 0 || s[1]/s[0] <= tolerance
+	}
+
+	// the similarity diag(1, 1, 1/k) * m * diag(1, 1, k) has the same
+	// invertibility but scales the translation column by k and the
+	// perspective row by 1/k, which are balanced against each other
+	// (or against the linear part when there is no perspective)
+	column := math.Hypot(m[0][2], m[1][2])
+	row := math.Hypot(m[2][0], m[2][1])
+	linear := math.Max(math.Max(math.Abs(m[0][0]), math.Abs(m[0][1])),
+		math.Max(math.Abs(m[1][0]), math.Abs(m[1][1])))
+	k := 1.0
+	switch {
+	case column > 0 && row > 0:
+		k = math.Sqrt(row / column)
+	case column > linear && linear > 0:
+		k = linear / column
+	}
+	balanced := Matrix3{
+		{m[0][0], m[0][1], m[0][2] * k},
+		{m[1][0], m[1][1], m[1][2] * k},
+		{m[2][0] / k, m[2][1] / k, m[2][2]},
+	}
+	return balanced.IsSingular(tolerance)
+}
+
+func cross3(a, b *[3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func normalize3(a *[3]float64) {
+	l := math.Sqrt(a[0]*a[0] + a[1]*a[1] + a[2]*a[2])
+	a[0] /= l
+	a[1] /= l
+	a[2] /= l
+}
@@ -0,0 +1,122 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func diagMatrix(a, b, c float64) *Matrix3 {
+	return &Matrix3{{a, 0, 0}, {0, b, 0}, {0, 0, c}}
+}
+
+func isOrthogonal(m *Matrix3) bool {
+	return m.GetTranspose().Multiply(m).CloseEnough(IdentityMatrix(), 1e-9)
+}
+
+func TestMatrixSVD(t *testing.T) {
+	u, s, v := pInput.SVD()
+	if !(s[0] >= s[1] && s[1] >= s[2] && s[2] >= 0) {
+		t.Error("singular values should be descending and non-negative")
+	}
+	if !isOrthogonal(u) || !isOrthogonal(v) {
+		t.Error("singular vectors should be orthogonal")
+	}
+	result := u.Multiply(diagMatrix(s[0], s[1], s[2])).Multiply(v.GetTranspose())
+	if !result.CloseEnough(pInput, 1e-9) {
+		t.Error("svd should reconstruct the original matrix")
+	}
+
+	singular := Matrix3{{1, 2, 3}, {2, 4, 6}, {1, 0, 1}}
+	u, s, v = singular.SVD()
+	if s[2] != 0 || !isOrthogonal(u) {
+		t.Error("singular matrix should have a zero singular value")
+	}
+	result = u.Multiply(diagMatrix(s[0], s[1], s[2])).Multiply(v.GetTranspose())
+	if !result.CloseEnough(&singular, 1e-9) {
+		t.Error("svd of a singular matrix should reconstruct it")
+	}
+}
+
+func TestMatrixSVD2(t *testing.T) {
+	m := Matrix3{{3, 1, 7}, {-2, 0.5, 9}, {0, 0, 1}}
+	u, s, v := m.SVD2()
+	result := u.Multiply(diagMatrix(s[0], s[1], 1)).Multiply(v.GetTranspose())
+	result[0][2], result[1][2] = 7, 9
+	if !result.CloseEnough(&m, 1e-9) || s[0] < s[1] || s[1] < 0 {
+		t.Error("2x2 svd should reconstruct the linear part")
+	}
+}
+
+func TestMatrixSymmetricEigen(t *testing.T) {
+	m := Matrix3{{4, 1, 2}, {1, 3, 0}, {2, 0, 5}}
+	values, q := m.SymmetricEigen()
+	result := q.Multiply(diagMatrix(values[0], values[1], values[2])).Multiply(q.GetTranspose())
+	if !result.CloseEnough(&m, 1e-9) || !isOrthogonal(q) {
+		t.Error("eigen decomposition should reconstruct the matrix")
+	}
+	if values[0] < values[1] || values[1] < values[2] {
+		t.Error("eigenvalues should be descending")
+	}
+}
+
+func TestMatrixPolarDecomposition(t *testing.T) {
+	m := RotationMatrix(0.6).Multiply(ScaleMatrix(2, 3))
+	r, p := m.PolarDecomposition()
+	if !r.CloseEnough(RotationMatrix(0.6), 1e-9) {
+		t.Error("polar decomposition should extract the rotation")
+	}
+	if !p.CloseEnough(ScaleMatrix(2, 3), 1e-9) {
+		t.Error("polar decomposition should extract the scale")
+	}
+}
+
+func TestMatrixSolve(t *testing.T) {
+	x, ok := pInput.Solve([3]float64{1, 2, 3})
+	if !ok {
+		t.Fatal("solve should succeed for an invertible matrix")
+	}
+	for row := 0; row < 3; row++ {
+		sum := 0.0
+		for col := 0; col < 3; col++ {
+			sum += pInput[row][col] * x[col]
+		}
+		if math.Abs(sum-float64(row+1)) > 1e-9 {
+			t.Error("solution should satisfy the system")
+		}
+	}
+	if _, ok := NewMatrix().Solve([3]float64{1, 2, 3}); ok {
+		t.Error("solve should fail for a singular matrix")
+	}
+}
+
+func TestMatrixNearSingularInverse(t *testing.T) {
+	nearly := diagMatrix(1, 1, 1e-14)
+	if nil != nearly.GetInverse() {
+		t.Error("near singular matrix should not be inverted by default")
+	}
+	if nil == nearly.GetInverseTolerance(1e-15) {
+		t.Error("near singular matrix should invert with a smaller tolerance")
+	}
+	if math.Abs(diagMatrix(1, 2, 4).ConditionNumber()-4) > 1e-12 {
+		t.Error("condition number should be the ratio of singular values")
+	}
+	if !math.IsInf(NewMatrix().ConditionNumber(), 1) {
+		t.Error("condition number of a singular matrix should be infinite")
+	}
+}
+
+func TestMatrixLargeTranslationInverse(t *testing.T) {
+	m := TranslationMatrix(1e6, 1e6)
+	inv := m.GetInverse()
+	if nil == inv || !m.Multiply(inv).CloseEnough(IdentityMatrix(), 1e-9) {
+		t.Error("large translation should be invertible")
+	}
+	projective := &Matrix3{{1, 0, 1e6}, {0, 1, 1e6}, {1e-3, 0, 1}}
+	inv = projective.GetInverse()
+	if nil == inv || !projective.Multiply(inv).CloseEnough(IdentityMatrix(), 1e-6) {
+		t.Error("projective matrix with a large translation should be invertible")
+	}
+	if nil != (&Matrix3{{1, 2, 1e6}, {2, 4, 1e6}, {0, 0, 1}}).GetInverse() {
+		t.Error("singular linear part should not be inverted")
+	}
+}
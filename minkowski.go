@@ -0,0 +1,306 @@
+package geo2
+
+import (
+	"math"
+	"sort"
+)
+
+// MinkowskiSumConvex returns the Minkowski sum of two convex paths,
+// the shape swept by one path as its origin is moved over the other,
+// by merging their edges in order of angle. Paths which are not convex
+// are replaced by their convex hulls. The result is wound counter clockwise
+func MinkowskiSumConvex(a, b *Path) *Path {
+	p, q := *a.ConvexHull(), *b.ConvexHull()
+	if 0 == len(p) || 0 == len(q) {
+		return &Path{}
+	}
+	if 1 == len(q) {
+		p, q = q, p
+	}
+	if 1 == len(p) {
+		result := make(Path, len(q))
+		for i, point := range q {
+			result[i] = point.Clone().Add(p[0])
+		}
+		return &result
+	}
+
+	lowest := func(points Path) int {
+		best := 0
+		for i, point := range points {
+			if point.Y < points[best].Y || (point.Y == points[best].Y && point.X < points[best].X) {
+				best = i
+			}
+		}
+		return best
+	}
+	n, m := len(p), len(q)
+	i0, j0 := lowest(p), lowest(q)
+	result := make(Path, 0, n+m)
+	for i, j := 0, 0; i < n || j < m; {
+		pi, qj := p[(i0+i)%n], q[(j0+j)%m]
+		result = append(result, pi.Clone().Add(qj))
+		if i == n {
+			j++
+			continue
+		}
+		if j == m {
+			i++
+			continue
+		}
+		e1 := p[(i0+i+1)%n].Clone().Sub(pi)
+		e2 := q[(j0+j+1)%m].Clone().Sub(qj)
+		cross := e1.Cross(e2)
+		switch {
+		case cross > 0:
+			i++
+		case cross < 0:
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return &result
+}
+
+// MinkowskiDifferenceConvex returns the Minkowski difference of two
+// convex paths, being the sum of a with b reflected through the origin.
+// The result contains the origin exactly when the paths overlap, and
+// is the region where the origin of b cannot be placed without b
+// overlapping a (its configuration space obstacle)
+func MinkowskiDifferenceConvex(a, b *Path) *Path {
+	return MinkowskiSumConvex(a, negatePath(b))
+}
+
+// MinkowskiSum returns the Minkowski sum of two simple polygons, which
+// may be concave. Each polygon is decomposed into convex pieces which
+// are summed in pairs and joined together, so the result is a list of
+// loops, with outer boundaries wound counter clockwise and any holes
+// wound clockwise
+func MinkowskiSum(a, b *Path) []*Path {
+	piecesA, piecesB := convexPieces(a), convexPieces(b)
+	if 1 == len(piecesA) && 1 == len(piecesB) {
+		return []*Path{MinkowskiSumConvex(piecesA[0], piecesB[0])}
+	}
+	var sums []*Path
+	for _, pa := range piecesA {
+		for _, pb := range piecesB {
+			sums = append(sums, MinkowskiSumConvex(pa, pb))
+		}
+	}
+	return polygonUnion(sums)
+}
+
+// MinkowskiDifference returns the Minkowski difference of two simple
+// polygons, being the sum of a with b reflected through the origin.
+// See MinkowskiSum and MinkowskiDifferenceConvex for details
+func MinkowskiDifference(a, b *Path) []*Path {
+	return MinkowskiSum(a, negatePath(b))
+}
+
+// negatePath returns a copy of the path reflected through the origin
+func negatePath(path *Path) *Path {
+	result := make(Path, len(*path))
+	for i, p := range *path {
+		result[i] = p.Clone().Negate()
+	}
+	return &result
+}
+
+// isConvex returns true if the given loop never turns clockwise
+// when wound counter clockwise
+func isConvex(points []*Vector) bool {
+	points = counterClockwise(points)
+	for i, p := range points {
+		if orient(points[(i+len(points)-1)%len(points)], p, points[(i+1)%len(points)]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// convexPieces splits the given path into convex pieces
+func convexPieces(path *Path) []*Path {
+	points := dedupeLoop(*path.Clone())
+	if len(points) < 3 || isConvex(points) {
+		return []*Path{path}
+	}
+	return path.ConvexDecomposition(nil)
+}
+
+// unionEdge is part of the boundary of a polygon being joined
+type unionEdge struct {
+	a, b *Vector
+	used bool
+}
+
+// polygonUnion joins the given simple polygons together, returning the
+// boundary of the area they cover with outer loops wound counter
+// clockwise and holes wound clockwise. Every edge is split where it
+// crosses another, and the pieces which lie inside another polygon or
+// between two touching polygons are discarded before the remaining
+// pieces are followed around into loops
+func polygonUnion(polygons []*Path) []*Path {
+	var loops [][]*Vector
+	var all []*Vector
+	for _, polygon := range polygons {
+		points := counterClockwise(dedupeLoop(*polygon.Clone()))
+		if len(points) < 3 || signedArea(points) <= 0 {
+			continue
+		}
+		loops = append(loops, points)
+		all = append(all, points...)
+	}
+	if 0 == len(loops) {
+		return nil
+	}
+	bounds := NewRectangleFromPoints(all)
+	tolerance := 1e-9 * (1 + math.Max(bounds.Width, bounds.Height))
+
+	// points within the tolerance of each other are snapped together
+	// so that the split edges can be followed from one to the next
+	var pool []*Vector
+	snap := func(point *Vector) *Vector {
+		for _, p := range pool {
+			if math.Abs(p.X-point.X) <= tolerance && math.Abs(p.Y-point.Y) <= tolerance {
+				return p
+			}
+		}
+		pool = append(pool, point)
+		return point
+	}
+
+	type source struct {
+		a, b *Vector
+		loop int
+	}
+	var sources []source
+	for l, loop := range loops {
+		for i, p := range loop {
+			sources = append(sources, source{p, loop[(i+1)%len(loop)], l})
+		}
+	}
+
+	directed := make(map[[2]*Vector]bool)
+	var order [][2]*Vector
+	for i, s := range sources {
+		dir := s.b.Clone().Sub(s.a)
+		lengthSqd := dir.LengthSqd()
+		cuts := []float64{0, 1}
+		for j, other := range sources {
+			if i == j {
+				continue
+			}
+			otherDir := other.b.Clone().Sub(other.a)
+			offset := other.a.Clone().Sub(s.a)
+			denom := dir.Cross(otherDir)
+			if math.Abs(denom) > 1e-12*lengthSqd {
+				t := offset.Cross(otherDir) / denom
+				u := offset.Cross(dir) / denom
+				if t > 0 && t < 1 && u >= -1e-12 && u <= 1+1e-12 {
+					cuts = append(cuts, t)
+				}
+				continue
+			}
+			// parallel edges only cut each other when they overlap
+			if math.Abs(offset.Cross(dir)) > tolerance*math.Sqrt(lengthSqd) {
+				continue
+			}
+			for _, p := range []*Vector{other.a, other.b} {
+				if t := p.Clone().Sub(s.a).Dot(dir) / lengthSqd; t > 0 && t < 1 {
+					cuts = append(cuts, t)
+				}
+			}
+		}
+		sort.Float64s(cuts)
+
+		for k := 1; k < len(cuts); k++ {
+			a := snap(NewLine(s.a, s.b).GetPosition(cuts[k-1]))
+			b := snap(NewLine(s.a, s.b).GetPosition(cuts[k]))
+			if a == b {
+				continue
+			}
+			middle := a.Clone().Add(b).MultiplyScalar(0.5)
+			covered := false
+			for l, loop := range loops {
+				if l != s.loop && insideLoop(loop, middle, tolerance) {
+					covered = true
+					break
+				}
+			}
+			key := [2]*Vector{a, b}
+			if !covered && !directed[key] {
+				directed[key] = true
+				order = append(order, key)
+			}
+		}
+	}
+
+	// edges running both ways lie between two touching polygons
+	var edges []*unionEdge
+	outgoing := make(map[*Vector][]*unionEdge)
+	for _, key := range order {
+		if directed[[2]*Vector{key[1], key[0]}] {
+			continue
+		}
+		edge := &unionEdge{a: key[0], b: key[1]}
+		edges = append(edges, edge)
+		outgoing[edge.a] = append(outgoing[edge.a], edge)
+	}
+
+	var result []*Path
+	for _, first := range edges {
+		if first.used {
+			continue
+		}
+		loop := Path{}
+		for edge := first; nil != edge && !edge.used; {
+			edge.used = true
+			loop = append(loop, edge.a)
+			// where loops touch, take the sharpest right turn
+			// to keep them apart
+			incoming := edge.b.Clone().Sub(edge.a)
+			var next *unionEdge
+			bestAngle := math.Inf(1)
+			for _, candidate := range outgoing[edge.b] {
+				if candidate.used {
+					continue
+				}
+				out := candidate.b.Clone().Sub(candidate.a)
+				angle := math.Atan2(incoming.Cross(out), incoming.Dot(out))
+				if angle < bestAngle {
+					next, bestAngle = candidate, angle
+				}
+			}
+			edge = next
+		}
+		if simplified := removeCollinear(loop, tolerance); len(simplified) >= 3 {
+			clean := make(Path, len(simplified))
+			for i, p := range simplified {
+				clean[i] = p.Clone()
+			}
+			result = append(result, &clean)
+		}
+	}
+	return result
+}
+
+// removeCollinear removes the points of the loop which lie on
+// the straight line between their neighbours
+func removeCollinear(loop []*Vector, tolerance float64) []*Vector {
+	result := append([]*Vector{}, loop...)
+	for changed := true; changed && len(result) >= 3; {
+		changed = false
+		for i := 0; i < len(result) && len(result) >= 3; i++ {
+			prev := result[(i+len(result)-1)%len(result)]
+			next := result[(i+1)%len(result)]
+			if NewLine(prev, next).DistanceToPoint(result[i], false) <= tolerance {
+				result = append(result[:i], result[i+1:]...)
+				changed = true
+				i--
+			}
+		}
+	}
+	return result
+}
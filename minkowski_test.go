@@ -0,0 +1,63 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMinkowskiSumConvex(t *testing.T) {
+	square := NewRectangle(0, 0, 1, 1).ToPath()
+	sum := MinkowskiSumConvex(square, square)
+	if 4 != len(*sum) || math.Abs(signedArea(*sum)-4) > 1e-9 {
+		t.Error("expected a 2x2 square")
+	}
+
+	triangle := &Path{NewVector(0, 0), NewVector(2, 0), NewVector(0, 2)}
+	sum = MinkowskiSumConvex(triangle, square)
+	// area of the triangle, the square and the edges of each swept along the other
+	if 5 != len(*sum) || math.Abs(signedArea(*sum)-(2+1+2+2)) > 1e-9 {
+		t.Error("expected a pentagon with area 7")
+	}
+
+	overlapping := MinkowskiDifferenceConvex(square, NewRectangle(0.5, 0.5, 1, 1).ToPath())
+	if !overlapping.Contains(NewVector(0, 0)) {
+		t.Error("difference of overlapping squares should contain the origin")
+	}
+	apart := MinkowskiDifferenceConvex(square, NewRectangle(3, 0, 1, 1).ToPath())
+	if apart.Contains(NewVector(0, 0)) {
+		t.Error("difference of separate squares should not contain the origin")
+	}
+}
+
+func TestMinkowskiSum(t *testing.T) {
+	robot := NewRectangle(-0.5, -0.5, 1, 1).ToPath()
+	ell := &Path{NewVector(0, 0), NewVector(4, 0), NewVector(4, 2), NewVector(2, 2), NewVector(2, 4), NewVector(0, 4)}
+	sum := MinkowskiSum(ell, robot)
+	if 1 != len(sum) || 6 != len(*sum[0]) || math.Abs(signedArea(*sum[0])-21) > 1e-9 {
+		t.Fatal("expected the L shape grown by 0.5 with area 21")
+	}
+
+	// the narrow slit in the top of the ring is closed by
+	// the robot, leaving a hole in the middle
+	ring := &Path{
+		NewVector(0, 0), NewVector(6, 0), NewVector(6, 6), NewVector(3.2, 6), NewVector(3.2, 5),
+		NewVector(5, 5), NewVector(5, 1), NewVector(1, 1), NewVector(1, 5), NewVector(2.8, 5),
+		NewVector(2.8, 6), NewVector(0, 6),
+	}
+	sum = MinkowskiSum(ring, robot)
+	if 2 != len(sum) {
+		t.Fatal("expected an outer loop and a hole")
+	}
+	areas := []float64{signedArea(*sum[0]), signedArea(*sum[1])}
+	if areas[0] < areas[1] {
+		areas[0], areas[1] = areas[1], areas[0]
+	}
+	if math.Abs(areas[0]-49) > 1e-9 || math.Abs(areas[1]+9) > 1e-9 {
+		t.Error("expected areas of 49 and -9")
+	}
+
+	difference := MinkowskiDifference(ell, robot)
+	if 1 != len(difference) || math.Abs(signedArea(*difference[0])-21) > 1e-9 {
+		t.Error("difference with a symmetric shape should match the sum")
+	}
+}
@@ -0,0 +1,318 @@
+package geo2
+
+import (
+	"container/heap"
+	"math"
+)
+
+// NavMesh is a navigation mesh made of connected triangles
+// which can be searched for the shortest path between two points
+type NavMesh struct {
+	// Triangles are the walkable triangles of this
+	// mesh, all wound counter clockwise
+	Triangles *TriangleList
+	// Neighbours lists the triangle across each edge of every
+	// triangle, where edge j runs from point j to point j+1,
+	// or -1 if the edge lies on the boundary of the mesh
+	Neighbours [][3]int
+
+	bvh      *BVH
+	boundary map[[2]float64]bool
+}
+
+// navEdge identifies an edge by the position of its end points
+type navEdge [4]float64
+
+func newNavEdge(a, b *Vector) navEdge {
+	if a.X < b.X || (a.X == b.X && a.Y < b.Y) {
+		return navEdge{a.X, a.Y, b.X, b.Y}
+	}
+	return navEdge{b.X, b.Y, a.X, a.Y}
+}
+
+// NewNavMesh creates a new navigation mesh from the given triangles.
+// Triangles are connected where they share an edge with the same
+// end points. The triangles are copied so the list can be reused
+func NewNavMesh(tris *TriangleList) *NavMesh {
+	triangles := make(TriangleList, 0, len(*tris))
+	for _, tri := range *tris {
+		points := counterClockwise([]*Vector{tri.Points[0].Clone(), tri.Points[1].Clone(), tri.Points[2].Clone()})
+		if 0 == signedArea(points) {
+			continue
+		}
+		triangles = append(triangles, NewTriangle(points))
+	}
+	mesh := &NavMesh{
+		Triangles:  &triangles,
+		Neighbours: make([][3]int, len(triangles)),
+		boundary:   make(map[[2]float64]bool),
+	}
+
+	type side struct{ tri, edge int }
+	edges := make(map[navEdge]side)
+	for i, tri := range triangles {
+		for j := 0; j < 3; j++ {
+			mesh.Neighbours[i][j] = -1
+			key := newNavEdge(tri.Points[j], tri.Points[(j+1)%3])
+			if other, ok := edges[key]; ok {
+				mesh.Neighbours[i][j] = other.tri
+				mesh.Neighbours[other.tri][other.edge] = i
+				delete(edges, key)
+				continue
+			}
+			edges[key] = side{i, j}
+		}
+	}
+	for _, s := range edges {
+		tri := triangles[s.tri]
+		for _, p := range []*Vector{tri.Points[s.edge], tri.Points[(s.edge+1)%3]} {
+			mesh.boundary[[2]float64{p.X, p.Y}] = true
+		}
+	}
+	mesh.bvh = NewBVH(mesh.Triangles, BVHSplitSAH)
+	return mesh
+}
+
+// NewNavMeshFromPolygon creates a new navigation mesh covering the
+// area within the given outer path but outside of each of the holes.
+// Returns nil if the polygon cannot be triangulated
+func NewNavMeshFromPolygon(outer *Path, holes []*Path) *NavMesh {
+	tris := outer.TriangulateWithHoles(holes)
+	if nil == tris || 0 == len(*tris) {
+		return nil
+	}
+	return NewNavMesh(tris)
+}
+
+// Locate returns the index of the triangle containing
+// the given point, or -1 if it lies outside of the mesh
+func (mesh *NavMesh) Locate(point *Vector) int {
+	return mesh.bvh.TriangleAt(point)
+}
+
+// portal returns the edge shared by the given triangle and its
+// neighbour as seen when crossing it, shrunk by the agent radius
+// at each end which lies on the boundary of the mesh
+func (mesh *NavMesh) portal(tri, edge int, radius float64) (left, right *Vector) {
+	points := (*mesh.Triangles)[tri].Points
+	left = points[(edge+1)%3].Clone()
+	right = points[edge].Clone()
+	if radius <= 0 {
+		return left, right
+	}
+	span := left.Clone().Sub(right)
+	length := span.Length()
+	leftWall := mesh.boundary[[2]float64{left.X, left.Y}]
+	rightWall := mesh.boundary[[2]float64{right.X, right.Y}]
+	if leftWall && rightWall && length <= 2*radius {
+		middle := left.Add(right).MultiplyScalar(0.5)
+		return middle, middle.Clone()
+	}
+	span.DivideScalar(length).MultiplyScalar(math.Min(radius, length))
+	if leftWall {
+		left.Sub(span)
+	}
+	if rightWall {
+		right.Add(span)
+	}
+	return left, right
+}
+
+// width returns how wide an agent can be when passing through the
+// given triangle between two of its edges, following the triangle
+// width calculation of Demyen's triangulation A*
+func (mesh *NavMesh) width(tri, from, to int) float64 {
+	points := (*mesh.Triangles)[tri].Points
+	// the two edges share the point c, with the third edge running a to b
+	shared := from
+	if (from+1)%3 != to {
+		shared = to
+	}
+	c := points[(shared+1)%3]
+	a, b := points[shared], points[(shared+2)%3]
+	third := (shared + 2) % 3
+	width := math.Min(c.Clone().Sub(a).Length(), c.Clone().Sub(b).Length())
+	if obtuse(c, a, b) || obtuse(c, b, a) {
+		return width
+	}
+	if -1 == mesh.Neighbours[tri][third] {
+		return NewLine(a, b).DistanceToPoint(c, true)
+	}
+	return mesh.searchWidth(c, tri, third, width)
+}
+
+// searchWidth looks beyond the given edge for boundary
+// edges closer to c than the current width
+func (mesh *NavMesh) searchWidth(c *Vector, tri, edge int, width float64) float64 {
+	points := (*mesh.Triangles)[tri].Points
+	u, v := points[edge], points[(edge+1)%3]
+	if obtuse(c, u, v) || obtuse(c, v, u) {
+		return width
+	}
+	dist := NewLine(u, v).DistanceToPoint(c, true)
+	if dist > width {
+		return width
+	}
+	next := mesh.Neighbours[tri][edge]
+	if -1 == next {
+		return dist
+	}
+	for j, n := range mesh.Neighbours[next] {
+		if n != tri {
+			width = mesh.searchWidth(c, next, j, width)
+		}
+	}
+	return width
+}
+
+// obtuse returns true if the angle at b in the triangle abc is at least 90°
+func obtuse(a, b, c *Vector) bool {
+	return a.Clone().Sub(b).Dot(c.Clone().Sub(b)) <= 0
+}
+
+// FindCorridor searches the mesh with A* for the chain of triangles
+// leading from the start point to the end point, skipping triangles
+// too narrow for an agent of the given radius to pass through. Returns nil if either
+// point is outside of the mesh or there is no route between them
+func (mesh *NavMesh) FindCorridor(start, end *Vector, radius float64) []int {
+	from, to := mesh.Locate(start), mesh.Locate(end)
+	if -1 == from || -1 == to {
+		return nil
+	}
+
+	count := len(*mesh.Triangles)
+	cost := make([]float64, count)
+	came := make([]int, count)
+	entered := make([]int, count)
+	position := make([]*Vector, count)
+	closed := make([]bool, count)
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		came[i] = -1
+	}
+	cost[from] = 0
+	position[from] = start
+	queue := &priorityQueue{{start.Clone().Sub(end).Length(), from}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(priorityItem).value.(int)
+		if closed[current] {
+			continue
+		}
+		if current == to {
+			break
+		}
+		closed[current] = true
+		for edge, next := range mesh.Neighbours[current] {
+			if -1 == next || closed[next] {
+				continue
+			}
+			if current != from && 2*radius > mesh.width(current, entered[current], edge) {
+				continue
+			}
+			left, right := mesh.portal(current, edge, radius)
+			// triangles are entered through the middle of their portal
+			entry := left.Add(right).MultiplyScalar(0.5)
+			g := cost[current] + entry.Clone().Sub(position[current]).Length()
+			if next == to {
+				g += entry.Clone().Sub(end).Length()
+			}
+			if g >= cost[next] {
+				continue
+			}
+			cost[next] = g
+			came[next] = current
+			for j, n := range mesh.Neighbours[next] {
+				if n == current {
+					entered[next] = j
+				}
+			}
+			position[next] = entry
+			heap.Push(queue, priorityItem{g + entry.Clone().Sub(end).Length(), next})
+		}
+	}
+	if from != to && -1 == came[to] {
+		return nil
+	}
+
+	var corridor []int
+	for tri := to; -1 != tri; tri = came[tri] {
+		corridor = append(corridor, tri)
+	}
+	for i, j := 0, len(corridor)-1; i < j; i, j = i+1, j-1 {
+		corridor[i], corridor[j] = corridor[j], corridor[i]
+	}
+	return corridor
+}
+
+// FindPath returns the shortest path between the start and end points
+// through the mesh, keeping an agent of the given radius away from
+// the corners of the mesh boundary. Returns nil if either point is
+// outside of the mesh or there is no route between them
+func (mesh *NavMesh) FindPath(start, end *Vector, radius float64) *Path {
+	corridor := mesh.FindCorridor(start, end, radius)
+	if nil == corridor {
+		return nil
+	}
+	lefts := []*Vector{start.Clone()}
+	rights := []*Vector{start.Clone()}
+	for i := 0; i < len(corridor)-1; i++ {
+		for edge, next := range mesh.Neighbours[corridor[i]] {
+			if next != corridor[i+1] {
+				continue
+			}
+			left, right := mesh.portal(corridor[i], edge, radius)
+			lefts = append(lefts, left)
+			rights = append(rights, right)
+			break
+		}
+	}
+	lefts = append(lefts, end.Clone())
+	rights = append(rights, end.Clone())
+	return stringPull(lefts, rights)
+}
+
+// stringPull finds the shortest path through the given portals
+// using the simple stupid funnel algorithm, where the first and
+// last portals are the start and end points
+func stringPull(lefts, rights []*Vector) *Path {
+	apex, left, right := lefts[0], lefts[0], rights[0]
+	apexIndex, leftIndex, rightIndex := 0, 0, 0
+	path := Path{apex.Clone()}
+	for i := 1; i < len(lefts); i++ {
+		l, r := lefts[i], rights[i]
+
+		// tighten the right side of the funnel
+		if orient(apex, right, r) >= 0 {
+			if apex.Compare(right) || orient(apex, left, r) < 0 {
+				right, rightIndex = r, i
+			} else {
+				// the right side crossed the left, which becomes a corner
+				path = append(path, left.Clone())
+				apex, apexIndex = left, leftIndex
+				left, right = apex, apex
+				leftIndex, rightIndex = apexIndex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+
+		// tighten the left side of the funnel
+		if orient(apex, left, l) <= 0 {
+			if apex.Compare(left) || orient(apex, right, l) > 0 {
+				left, leftIndex = l, i
+			} else {
+				path = append(path, right.Clone())
+				apex, apexIndex = right, rightIndex
+				left, right = apex, apex
+				leftIndex, rightIndex = apexIndex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+	}
+	end := lefts[len(lefts)-1]
+	if !path[len(path)-1].Compare(end) {
+		path = append(path, end.Clone())
+	}
+	return &path
+}
@@ -0,0 +1,57 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func pathLength(path *Path) float64 {
+	length := 0.0
+	for i := 1; i < len(*path); i++ {
+		length += (*path)[i].Clone().Sub((*path)[i-1]).Length()
+	}
+	return length
+}
+
+func TestNavMeshFindPath(t *testing.T) {
+	mesh := NewNavMeshFromPolygon(NewRectangle(0, 0, 10, 10).ToPath(), []*Path{NewRectangle(4, 1, 2, 8).ToPath()})
+	if nil == mesh {
+		t.Fatal("expected a navigation mesh")
+	}
+	start, end := NewVector(2, 5), NewVector(8, 5)
+	path := mesh.FindPath(start, end, 0)
+	if nil == path || 4 != len(*path) {
+		t.Fatal("path should go around the wall with 4 points")
+	}
+	if !(*path)[0].Compare(start) || !(*path)[3].Compare(end) {
+		t.Error("path should run from start to end")
+	}
+	if length := pathLength(path); math.Abs(length-(2*math.Sqrt(20)+2)) > 1e-9 {
+		t.Error("path should take the shortest route around a corner of the wall")
+	}
+
+	if nil == mesh.FindPath(start, end, 0.4) {
+		t.Error("agent should fit through the 1 unit gaps")
+	}
+	if nil != mesh.FindPath(start, end, 0.6) {
+		t.Error("agent should not fit through the 1 unit gaps")
+	}
+	if nil != mesh.FindPath(start, NewVector(5, 5), 0) {
+		t.Error("point inside the wall should not be reachable")
+	}
+}
+
+func TestNavMeshFromTriangles(t *testing.T) {
+	mesh := NewNavMesh(gridTriangles(4))
+	for i, neighbours := range mesh.Neighbours {
+		for _, n := range neighbours {
+			if -1 != n && i != mesh.Neighbours[n][0] && i != mesh.Neighbours[n][1] && i != mesh.Neighbours[n][2] {
+				t.Error("triangles should be neighbours of their neighbours")
+			}
+		}
+	}
+	path := mesh.FindPath(NewVector(0.5, 0.5), NewVector(3.5, 3.2), 0)
+	if nil == path || 2 != len(*path) {
+		t.Error("path across the open grid should be straight")
+	}
+}
@@ -0,0 +1,164 @@
+package geo2
+
+import "math"
+
+// OrientedRectangle represents a 2D rectangle which
+// may be rotated about its center
+type OrientedRectangle struct {
+	Center      *Vector
+	HalfExtents *Vector
+	Rotation    float64
+}
+
+// NewOrientedRectangle creates a new oriented rectangle from the given
+// center, half width and height and rotation (in radians)
+func NewOrientedRectangle(center, halfExtents *Vector, rotation float64) *OrientedRectangle {
+	return &OrientedRectangle{center, halfExtents, rotation}
+}
+
+// NewOrientedRectangleFromRectangle creates the oriented rectangle
+// covered by the given rectangle once transformed by the given matrix.
+// The orientation follows the transformed x axis of the rectangle, so
+// for matrices containing shear the result is the smallest rectangle
+// with that orientation which bounds the transformed corners
+func NewOrientedRectangleFromRectangle(rect *Rectangle, m *Matrix3) *OrientedRectangle {
+	axis := NewVector(m[0][0], m[1][0])
+	if 0 == axis.LengthSqd() {
+		axis.Set(1, 0)
+	}
+	return orientedRectangleFromPoints(*rect.TransformToPath(m), axis.ToRotation())
+}
+
+// FitOrientedRectangle fits an oriented rectangle to the points of the
+// given path, aligned with their principal axes (PCA) and just large
+// enough to contain every point. Returns nil if the path is empty
+func FitOrientedRectangle(path *Path) *OrientedRectangle {
+	if 0 == len(*path) {
+		return nil
+	}
+	mean := NewVector(0, 0)
+	for _, p := range *path {
+		mean.Add(p)
+	}
+	mean.DivideScalar(float64(len(*path)))
+
+	var cxx, cxy, cyy float64
+	for _, p := range *path {
+		d := p.Clone().Sub(mean)
+		cxx += d.X * d.X
+		cxy += d.X * d.Y
+		cyy += d.Y * d.Y
+	}
+	angle := 0.5 * math.Atan2(2*cxy, cxx-cyy)
+	return orientedRectangleFromPoints(*path, angle)
+}
+
+// orientedRectangleFromPoints creates the smallest oriented rectangle
+// with the given rotation which contains all of the given points
+func orientedRectangleFromPoints(points []*Vector, rotation float64) *OrientedRectangle {
+	axisX := new(Vector).FromRotation(rotation, 1)
+	axisY := NewVector(-axisX.Y, axisX.X)
+	minX, maxX := projectPoints(points, axisX)
+	minY, maxY := projectPoints(points, axisY)
+	center := axisX.Clone().MultiplyScalar((minX + maxX) * 0.5).
+		Add(axisY.Clone().MultiplyScalar((minY + maxY) * 0.5))
+	return NewOrientedRectangle(
+		center,
+		NewVector((maxX-minX)*0.5, (maxY-minY)*0.5),
+		rotation,
+	)
+}
+
+// Clone creates a copy of this oriented rectangle
+func (o *OrientedRectangle) Clone() *OrientedRectangle {
+	return NewOrientedRectangle(o.Center.Clone(), o.HalfExtents.Clone(), o.Rotation)
+}
+
+// Axes returns the unit vectors along the local
+// x and y axes of this oriented rectangle
+func (o *OrientedRectangle) Axes() (*Vector, *Vector) {
+	x := new(Vector).FromRotation(o.Rotation, 1)
+	return x, NewVector(-x.Y, x.X)
+}
+
+// Corners returns the corners of this oriented rectangle, in the
+// same order as Rectangle.Corners for an unrotated rectangle
+func (o *OrientedRectangle) Corners() []*Vector {
+	x, y := o.Axes()
+	x.MultiplyScalar(o.HalfExtents.X)
+	y.MultiplyScalar(o.HalfExtents.Y)
+	return []*Vector{
+		o.Center.Clone().Sub(x).Sub(y),
+		o.Center.Clone().Add(x).Sub(y),
+		o.Center.Clone().Add(x).Add(y),
+		o.Center.Clone().Sub(x).Add(y),
+	}
+}
+
+// ToPath returns the corners of this oriented rectangle as a path
+func (o *OrientedRectangle) ToPath() *Path {
+	path := Path(o.Corners())
+	return &path
+}
+
+// Bounds returns the axis aligned rectangle
+// which contains this oriented rectangle
+func (o *OrientedRectangle) Bounds() *Rectangle {
+	return NewRectangleFromPoints(o.Corners())
+}
+
+// Area returns the area of this oriented rectangle
+func (o *OrientedRectangle) Area() float64 {
+	return math.Abs(4 * o.HalfExtents.X * o.HalfExtents.Y)
+}
+
+// Contains returns true if the given point is
+// within this oriented rectangle
+func (o *OrientedRectangle) Contains(vec *Vector) bool {
+	x, y := o.Axes()
+	d := vec.Clone().Sub(o.Center)
+	return (math.Abs(d.Dot(x)) <= math.Abs(o.HalfExtents.X) &&
+		math.Abs(d.Dot(y)) <= math.Abs(o.HalfExtents.Y))
+}
+
+// Transform applies the given matrix to this oriented rectangle in
+// place (see NewOrientedRectangleFromRectangle for how shear is handled)
+func (o *OrientedRectangle) Transform(m *Matrix3) {
+	x, _ := o.Axes()
+	axis := NewVector(
+		x.X*m[0][0]+x.Y*m[0][1],
+		x.X*m[1][0]+x.Y*m[1][1],
+	)
+	if 0 == axis.LengthSqd() {
+		axis = x
+	}
+	corners := o.ToPath()
+	corners.Transform(m)
+	*o = *orientedRectangleFromPoints(*corners, axis.ToRotation())
+}
+
+// Transformed returns a copy of this oriented
+// rectangle transformed by the given matrix
+func (o *OrientedRectangle) Transformed(m *Matrix3) Transformable {
+	clone := o.Clone()
+	clone.Transform(m)
+	return clone
+}
+
+// Overlaps returns true if this oriented rectangle overlaps
+// or touches the given one (using the separating axis theorem)
+func (o *OrientedRectangle) Overlaps(o2 *OrientedRectangle) bool {
+	return convexOverlap(o.Corners(), o2.Corners())
+}
+
+// OverlapsRectangle returns true if this oriented rectangle
+// overlaps or touches the given rectangle
+func (o *OrientedRectangle) OverlapsRectangle(rect *Rectangle) bool {
+	return convexOverlap(o.Corners(), rect.Corners())
+}
+
+// OverlapsTriangle returns true if this oriented rectangle
+// overlaps or touches the given triangle
+func (o *OrientedRectangle) OverlapsTriangle(tri *Triangle) bool {
+	return convexOverlap(o.Corners(), tri.Points)
+}
@@ -0,0 +1,74 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOrientedRectangleFromRectangle(t *testing.T) {
+	rect := NewRectangle(-2, -1, 4, 2)
+	m := TranslationMatrix(5, 5).Multiply(RotationMatrix(math.Pi / 6))
+	o := NewOrientedRectangleFromRectangle(rect, m)
+	if !o.Center.CloseEnough(NewVector(5, 5), 0.0001) ||
+		!o.HalfExtents.CloseEnough(NewVector(2, 1), 0.0001) ||
+		math.Abs(o.Rotation-math.Pi/6) > 1e-9 {
+		t.Error("oriented rectangle should follow the transform")
+	}
+	corners := o.Corners()
+	expected := rect.TransformToPath(m)
+	for i := range corners {
+		if !corners[i].CloseEnough((*expected)[i], 0.0001) {
+			t.Error("corners should match the transformed rectangle")
+		}
+	}
+}
+
+func TestFitOrientedRectangle(t *testing.T) {
+	rect := NewRectangle(-5, -1, 10, 2)
+	m := RotationMatrix(0.4)
+	o := FitOrientedRectangle(rect.TransformToPath(m))
+	if math.Abs(o.Area()-20) > 1e-6 || !o.Center.CloseEnough(NewVector(0, 0), 0.0001) {
+		t.Error("fitted rectangle should hug the rotated rectangle")
+	}
+	if !o.Contains(NewVector(0, 0)) || o.Contains(NewVector(0, 3)) {
+		t.Error("fitted rectangle should contain its center only")
+	}
+}
+
+func TestOrientedRectangleTransform(t *testing.T) {
+	o := NewOrientedRectangle(NewVector(1, 0), NewVector(1, 2), 0)
+	moved := o.Transformed(RotationMatrix(math.Pi / 2)).(*OrientedRectangle)
+	if !moved.Center.CloseEnough(NewVector(0, 1), 0.0001) ||
+		!moved.HalfExtents.CloseEnough(NewVector(1, 2), 0.0001) {
+		t.Error("transform should move and rotate the rectangle")
+	}
+	if bounds := moved.Bounds(); math.Abs(bounds.Width-4) > 1e-9 {
+		t.Error("bounds of the rotated rectangle should swap its size")
+	}
+}
+
+func TestOrientedRectangleOverlaps(t *testing.T) {
+	diamond := NewOrientedRectangle(NewVector(0, 0), NewVector(1, 1), math.Pi/4)
+	if !diamond.OverlapsRectangle(NewRectangle(1, -0.5, 1, 1)) {
+		t.Error("diamond tip should overlap the rectangle")
+	}
+	if diamond.OverlapsRectangle(NewRectangle(1.2, 1.2, 1, 1)) {
+		t.Error("rectangle off the diamond edge should not overlap")
+	}
+	other := NewOrientedRectangle(NewVector(2.5, 0), NewVector(1, 0.2), 0)
+	if diamond.Overlaps(other) {
+		t.Error("separated rectangles should not overlap")
+	}
+	other.Center.X = 2.3
+	if !diamond.Overlaps(other) {
+		t.Error("intersecting rectangles should overlap")
+	}
+	tri := NewTriangle([]*Vector{NewVector(1, 1), NewVector(3, 1), NewVector(1, 3)})
+	if diamond.OverlapsTriangle(tri) {
+		t.Error("triangle beyond the diamond edge should not overlap")
+	}
+	tri.Points[0].Set(0.5, 0.5)
+	if !diamond.OverlapsTriangle(tri) {
+		t.Error("triangle reaching into the diamond should overlap")
+	}
+}
@@ -0,0 +1,578 @@
+package geo2
+
+import (
+	"math"
+	"sort"
+)
+
+// PackHeuristic selects how a bin packer chooses
+// where each rectangle should be placed
+type PackHeuristic int
+
+const (
+	// PackBestShortSideFit minimizes the shorter leftover side
+	// of the free space a rectangle is placed into
+	PackBestShortSideFit PackHeuristic = iota
+	// PackBestLongSideFit minimizes the longer leftover side
+	// of the free space a rectangle is placed into
+	PackBestLongSideFit
+	// PackBestAreaFit places each rectangle into the smallest free
+	// space it fits in (for skylines, the one wasting the least area)
+	PackBestAreaFit
+	// PackTopLeft places each rectangle as close to the
+	// top of the bin as possible, then as far left
+	PackTopLeft
+	// PackContactPoint places each rectangle where it touches the most
+	// of the bin edges and other rectangles (MaxRects only)
+	PackContactPoint
+)
+
+// PackAlgorithm selects the packing algorithm used by Pack
+type PackAlgorithm int
+
+const (
+	// PackMaxRects tracks every maximal free rectangle in each bin.
+	// It is the slowest algorithm but usually packs the tightest
+	PackMaxRects PackAlgorithm = iota
+	// PackSkyline tracks the top edge of the placed rectangles.
+	// It is fast but cannot fill holes left under the skyline
+	PackSkyline
+	// PackGuillotine recursively splits the free space of each bin
+	// into two rectangles every time a rectangle is placed
+	PackGuillotine
+)
+
+// BinPacker places rectangles into a single bin
+type BinPacker interface {
+	// Insert places a rectangle of the given size in this bin,
+	// returning where it was placed and whether it was rotated by
+	// 90 degrees, or nil if there is no room for it
+	Insert(width, height float64) (*Rectangle, bool)
+	// Occupancy returns the fraction of this bin which is used
+	Occupancy() float64
+}
+
+// PackOptions configures Pack. The zero value packs with
+// MaxRects using the best short side fit and no rotation
+type PackOptions struct {
+	Algorithm     PackAlgorithm
+	Heuristic     PackHeuristic
+	AllowRotation bool
+	// Padding is the space left between neighboring rectangles
+	// (but not between the rectangles and the edges of the bin)
+	Padding float64
+	// MaxBins limits the number of bins which are
+	// used, or any number of bins when zero
+	MaxBins int
+}
+
+// PackedRectangle describes where a single rectangle was placed
+type PackedRectangle struct {
+	// Index is the position of the rectangle in the packed slice
+	Index int
+	// Bin is the index of the bin the rectangle was placed in
+	Bin int
+	// Rectangle is the position and size of the placed rectangle
+	// within the bin (with the width and height swapped if rotated)
+	Rectangle *Rectangle
+	// Rotated is true if the rectangle was rotated by 90 degrees
+	Rotated bool
+}
+
+// PackResult is the outcome of packing rectangles into bins
+type PackResult struct {
+	// Placements lists the placed rectangles in the order they were given
+	Placements []*PackedRectangle
+	// Unplaced lists the indices of any rectangles which did
+	// not fit (because they are too big or MaxBins was reached)
+	Unplaced []int
+	// Occupancy holds the fraction of each bin which is used
+	Occupancy []float64
+}
+
+// Pack places the sizes of the given rectangles into as many bins of
+// the given size as needed (their positions are ignored). Rectangles
+// are placed largest first into the first bin they fit in
+func Pack(sizes []*Rectangle, binWidth, binHeight float64, opts *PackOptions) *PackResult {
+	if nil == opts {
+		opts = &PackOptions{}
+	}
+	pad := opts.Padding
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := sizes[order[i]], sizes[order[j]]
+		return a.Area() > b.Area()
+	})
+
+	var bins []BinPacker
+	placed := make([]*PackedRectangle, len(sizes))
+	result := &PackResult{}
+	for _, idx := range order {
+		w := math.Abs(sizes[idx].Width)
+		h := math.Abs(sizes[idx].Height)
+		var rect *Rectangle
+		var rotated bool
+		bin := 0
+		for ; bin < len(bins); bin++ {
+			if rect, rotated = bins[bin].Insert(w+pad, h+pad); nil != rect {
+				break
+			}
+		}
+		if nil == rect && (opts.MaxBins <= 0 || len(bins) < opts.MaxBins) {
+			packer := NewBinPacker(opts.Algorithm, binWidth+pad, binHeight+pad, opts.Heuristic, opts.AllowRotation)
+			if rect, rotated = packer.Insert(w+pad, h+pad); nil != rect {
+				bins = append(bins, packer)
+			}
+		}
+		if nil == rect {
+			result.Unplaced = append(result.Unplaced, idx)
+			continue
+		}
+		rect.Width -= pad
+		rect.Height -= pad
+		placed[idx] = &PackedRectangle{idx, bin, rect, rotated}
+	}
+
+	for _, p := range placed {
+		if nil != p {
+			result.Placements = append(result.Placements, p)
+		}
+	}
+	sort.Ints(result.Unplaced)
+	// measure the occupancy without the padding
+	result.Occupancy = make([]float64, len(bins))
+	if area := binWidth * binHeight; area > 0 {
+		for _, p := range result.Placements {
+			result.Occupancy[p.Bin] += p.Rectangle.Area() / area
+		}
+	}
+	return result
+}
+
+// NewBinPacker creates an empty bin of the given size
+// which packs using the given algorithm
+func NewBinPacker(algorithm PackAlgorithm, width, height float64,
+	heuristic PackHeuristic, allowRotation bool) BinPacker {
+	switch algorithm {
+	case PackSkyline:
+		return NewSkylineBin(width, height, heuristic, allowRotation)
+	case PackGuillotine:
+		return NewGuillotineBin(width, height, heuristic, allowRotation)
+	}
+	return NewMaxRectsBin(width, height, heuristic, allowRotation)
+}
+
+// packScore orders candidate placements, lower is better
+type packScore struct {
+	primary, secondary float64
+}
+
+func (s packScore) less(other packScore) bool {
+	return s.primary < other.primary ||
+		(s.primary == other.primary && s.secondary < other.secondary)
+}
+
+var worstPackScore = packScore{math.Inf(1), math.Inf(1)}
+
+// freeSpaceScore scores placing a rectangle into free space
+// of the given size using the short side, long side or area fit
+func freeSpaceScore(heuristic PackHeuristic, freeW, freeH, w, h float64) packScore {
+	leftW, leftH := freeW-w, freeH-h
+	short, long := math.Min(leftW, leftH), math.Max(leftW, leftH)
+	switch heuristic {
+	case PackBestLongSideFit:
+		return packScore{long, short}
+	case PackBestAreaFit:
+		return packScore{freeW*freeH - w*h, short}
+	}
+	return packScore{short, long}
+}
+
+// MaxRectsBin packs rectangles into a single bin using
+// the MaxRects algorithm
+type MaxRectsBin struct {
+	width, height float64
+	heuristic     PackHeuristic
+	allowRotation bool
+	used          []*Rectangle
+	free          []*Rectangle
+}
+
+// NewMaxRectsBin creates an empty MaxRects bin of the given size
+func NewMaxRectsBin(width, height float64, heuristic PackHeuristic, allowRotation bool) *MaxRectsBin {
+	return &MaxRectsBin{
+		width:         width,
+		height:        height,
+		heuristic:     heuristic,
+		allowRotation: allowRotation,
+		free:          []*Rectangle{NewRectangle(0, 0, width, height)},
+	}
+}
+
+// Insert places a rectangle of the given size in this bin
+// (see BinPacker)
+func (bin *MaxRectsBin) Insert(width, height float64) (*Rectangle, bool) {
+	var best *Rectangle
+	bestScore := worstPackScore
+	rotated := false
+	for _, free := range bin.free {
+		for r, size := range [2][2]float64{{width, height}, {height, width}} {
+			if 1 == r && (!bin.allowRotation || width == height) {
+				break
+			}
+			w, h := size[0], size[1]
+			if w > free.Width || h > free.Height {
+				continue
+			}
+			score := bin.score(free, w, h)
+			if score.less(bestScore) {
+				best, bestScore, rotated = NewRectangle(free.X, free.Y, w, h), score, 1 == r
+			}
+		}
+	}
+	if nil == best {
+		return nil, false
+	}
+
+	var free []*Rectangle
+	for _, f := range bin.free {
+		free = append(free, splitFreeRectangle(f, best)...)
+	}
+	bin.free = pruneFreeRectangles(free)
+	bin.used = append(bin.used, best)
+	return best.Clone(), rotated
+}
+
+// score rates placing a rectangle of the given
+// size at the top left of the given free space
+func (bin *MaxRectsBin) score(free *Rectangle, w, h float64) packScore {
+	switch bin.heuristic {
+	case PackTopLeft:
+		return packScore{free.Y + h, free.X}
+	case PackContactPoint:
+		return packScore{-bin.contact(free.X, free.Y, w, h), 0}
+	}
+	return freeSpaceScore(bin.heuristic, free.Width, free.Height, w, h)
+}
+
+// contact returns the length of the edges of the given placement
+// which touch the edges of the bin or already placed rectangles
+func (bin *MaxRectsBin) contact(x, y, w, h float64) float64 {
+	total := 0.0
+	if 0 == x || x+w == bin.width {
+		total += h
+	}
+	if 0 == y || y+h == bin.height {
+		total += w
+	}
+	for _, u := range bin.used {
+		if u.X == x+w || u.Right() == x {
+			total += math.Max(0, math.Min(u.Bottom(), y+h)-math.Max(u.Y, y))
+		}
+		if u.Y == y+h || u.Bottom() == y {
+			total += math.Max(0, math.Min(u.Right(), x+w)-math.Max(u.X, x))
+		}
+	}
+	return total
+}
+
+// Occupancy returns the fraction of this bin which is used
+func (bin *MaxRectsBin) Occupancy() float64 {
+	return occupancy(bin.used, bin.width, bin.height)
+}
+
+// splitFreeRectangle returns the maximal free rectangles left
+// within free once the used rectangle has been placed
+func splitFreeRectangle(free, used *Rectangle) []*Rectangle {
+	if used.X >= free.Right() || used.Right() <= free.X ||
+		used.Y >= free.Bottom() || used.Bottom() <= free.Y {
+		return []*Rectangle{free}
+	}
+	var result []*Rectangle
+	if used.X > free.X {
+		result = append(result, NewRectangle(free.X, free.Y, used.X-free.X, free.Height))
+	}
+	if used.Right() < free.Right() {
+		result = append(result, NewRectangle(used.Right(), free.Y, free.Right()-used.Right(), free.Height))
+	}
+	if used.Y > free.Y {
+		result = append(result, NewRectangle(free.X, free.Y, free.Width, used.Y-free.Y))
+	}
+	if used.Bottom() < free.Bottom() {
+		result = append(result, NewRectangle(free.X, used.Bottom(), free.Width, free.Bottom()-used.Bottom()))
+	}
+	return result
+}
+
+// pruneFreeRectangles removes any free rectangles
+// which are contained within another
+func pruneFreeRectangles(free []*Rectangle) []*Rectangle {
+	var result []*Rectangle
+	for i, a := range free {
+		contained := false
+		for j, b := range free {
+			if i != j && b.ContainsRectangle(a) && (!a.ContainsRectangle(b) || j < i) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// SkylineBin packs rectangles into a single bin by
+// tracking the skyline formed by their top edges
+type SkylineBin struct {
+	width, height float64
+	heuristic     PackHeuristic
+	allowRotation bool
+	used          []*Rectangle
+	skyline       []skylineNode
+}
+
+type skylineNode struct {
+	x, y, width float64
+}
+
+// NewSkylineBin creates an empty skyline bin of the given size. Only
+// PackTopLeft and PackBestAreaFit (minimum waste) are supported,
+// other heuristics fall back to PackTopLeft
+func NewSkylineBin(width, height float64, heuristic PackHeuristic, allowRotation bool) *SkylineBin {
+	return &SkylineBin{
+		width:         width,
+		height:        height,
+		heuristic:     heuristic,
+		allowRotation: allowRotation,
+		skyline:       []skylineNode{{0, 0, width}},
+	}
+}
+
+// Insert places a rectangle of the given size in this bin
+// (see BinPacker)
+func (bin *SkylineBin) Insert(width, height float64) (*Rectangle, bool) {
+	var best *Rectangle
+	bestIndex := -1
+	bestScore := worstPackScore
+	rotated := false
+	for i := range bin.skyline {
+		for r, size := range [2][2]float64{{width, height}, {height, width}} {
+			if 1 == r && (!bin.allowRotation || width == height) {
+				break
+			}
+			w, h := size[0], size[1]
+			y, ok := bin.fit(i, w, h)
+			if !ok {
+				continue
+			}
+			score := packScore{y + h, bin.skyline[i].width}
+			if PackBestAreaFit == bin.heuristic {
+				score = packScore{bin.waste(i, y, w), y + h}
+			}
+			if score.less(bestScore) {
+				best, bestIndex, bestScore, rotated = NewRectangle(bin.skyline[i].x, y, w, h), i, score, 1 == r
+			}
+		}
+	}
+	if nil == best {
+		return nil, false
+	}
+	bin.place(bestIndex, best)
+	bin.used = append(bin.used, best)
+	return best.Clone(), rotated
+}
+
+// fit returns the height at which a rectangle of the given
+// size would rest if placed at the start of the given node
+func (bin *SkylineBin) fit(index int, w, h float64) (float64, bool) {
+	x := bin.skyline[index].x
+	if x+w > bin.width {
+		return 0, false
+	}
+	y := 0.0
+	for i := index; i < len(bin.skyline) && bin.skyline[i].x < x+w; i++ {
+		y = math.Max(y, bin.skyline[i].y)
+		if y+h > bin.height {
+			return 0, false
+		}
+	}
+	return y, true
+}
+
+// waste returns the area left empty below a rectangle of
+// the given width placed at height y on the given node
+func (bin *SkylineBin) waste(index int, y, w float64) float64 {
+	x := bin.skyline[index].x
+	total := 0.0
+	for i := index; i < len(bin.skyline) && bin.skyline[i].x < x+w; i++ {
+		node := bin.skyline[i]
+		right := math.Min(node.x+node.width, x+w)
+		total += (right - node.x) * (y - node.y)
+	}
+	return total
+}
+
+// place raises the skyline over the given placed rectangle
+func (bin *SkylineBin) place(index int, rect *Rectangle) {
+	node := skylineNode{rect.X, rect.Bottom(), rect.Width}
+	var skyline []skylineNode
+	skyline = append(skyline, bin.skyline[:index]...)
+	skyline = append(skyline, node)
+	for _, n := range bin.skyline[index:] {
+		right := n.x + n.width
+		if right <= rect.Right() {
+			continue
+		}
+		if n.x < rect.Right() {
+			n.width = right - rect.Right()
+			n.x = rect.Right()
+		}
+		skyline = append(skyline, n)
+	}
+	// merge neighboring nodes at the same height
+	merged := skyline[:1]
+	for _, n := range skyline[1:] {
+		last := &merged[len(merged)-1]
+		if last.y == n.y {
+			last.width += n.width
+			continue
+		}
+		merged = append(merged, n)
+	}
+	bin.skyline = merged
+}
+
+// Occupancy returns the fraction of this bin which is used
+func (bin *SkylineBin) Occupancy() float64 {
+	return occupancy(bin.used, bin.width, bin.height)
+}
+
+// GuillotineBin packs rectangles into a single bin by splitting the
+// free space into two rectangles each time one is placed
+type GuillotineBin struct {
+	width, height float64
+	heuristic     PackHeuristic
+	allowRotation bool
+	used          []*Rectangle
+	free          []*Rectangle
+}
+
+// NewGuillotineBin creates an empty guillotine bin of the given size.
+// Only the short side, long side and area fit heuristics are supported,
+// other heuristics fall back to PackBestAreaFit. The leftover space is
+// always split along its shorter axis
+func NewGuillotineBin(width, height float64, heuristic PackHeuristic, allowRotation bool) *GuillotineBin {
+	if PackTopLeft == heuristic || PackContactPoint == heuristic {
+		heuristic = PackBestAreaFit
+	}
+	return &GuillotineBin{
+		width:         width,
+		height:        height,
+		heuristic:     heuristic,
+		allowRotation: allowRotation,
+		free:          []*Rectangle{NewRectangle(0, 0, width, height)},
+	}
+}
+
+// Insert places a rectangle of the given size in this bin
+// (see BinPacker)
+func (bin *GuillotineBin) Insert(width, height float64) (*Rectangle, bool) {
+	bestIndex := -1
+	bestScore := worstPackScore
+	var bestW, bestH float64
+	rotated := false
+	for i, free := range bin.free {
+		for r, size := range [2][2]float64{{width, height}, {height, width}} {
+			if 1 == r && (!bin.allowRotation || width == height) {
+				break
+			}
+			w, h := size[0], size[1]
+			if w > free.Width || h > free.Height {
+				continue
+			}
+			score := freeSpaceScore(bin.heuristic, free.Width, free.Height, w, h)
+			if w == free.Width && h == free.Height {
+				// perfect fits are always taken
+				score = packScore{math.Inf(-1), 0}
+			}
+			if score.less(bestScore) {
+				bestIndex, bestScore, bestW, bestH, rotated = i, score, w, h, 1 == r
+			}
+		}
+	}
+	if bestIndex < 0 {
+		return nil, false
+	}
+
+	free := bin.free[bestIndex]
+	placed := NewRectangle(free.X, free.Y, bestW, bestH)
+	bin.free = append(bin.free[:bestIndex], bin.free[bestIndex+1:]...)
+
+	// split the leftover L shape along its shorter axis
+	leftW, leftH := free.Width-bestW, free.Height-bestH
+	var bottom, right *Rectangle
+	if leftW <= leftH {
+		bottom = NewRectangle(free.X, placed.Bottom(), free.Width, leftH)
+		right = NewRectangle(placed.Right(), free.Y, leftW, bestH)
+	} else {
+		bottom = NewRectangle(free.X, placed.Bottom(), bestW, leftH)
+		right = NewRectangle(placed.Right(), free.Y, leftW, free.Height)
+	}
+	for _, r := range []*Rectangle{bottom, right} {
+		if r.Width > 0 && r.Height > 0 {
+			bin.free = append(bin.free, r)
+		}
+	}
+	bin.mergeFree()
+	bin.used = append(bin.used, placed)
+	return placed.Clone(), rotated
+}
+
+// mergeFree joins pairs of free rectangles which
+// together form a single larger rectangle
+func (bin *GuillotineBin) mergeFree() {
+	for i := 0; i < len(bin.free); i++ {
+		for j := i + 1; j < len(bin.free); j++ {
+			a, b := bin.free[i], bin.free[j]
+			switch {
+			case a.Width == b.Width && a.X == b.X && a.Bottom() == b.Y:
+				a.Height += b.Height
+			case a.Width == b.Width && a.X == b.X && b.Bottom() == a.Y:
+				a.Y = b.Y
+				a.Height += b.Height
+			case a.Height == b.Height && a.Y == b.Y && a.Right() == b.X:
+				a.Width += b.Width
+			case a.Height == b.Height && a.Y == b.Y && b.Right() == a.X:
+				a.X = b.X
+				a.Width += b.Width
+			default:
+				continue
+			}
+			bin.free = append(bin.free[:j], bin.free[j+1:]...)
+			j = i
+		}
+	}
+}
+
+// Occupancy returns the fraction of this bin which is used
+func (bin *GuillotineBin) Occupancy() float64 {
+	return occupancy(bin.used, bin.width, bin.height)
+}
+
+// occupancy returns the fraction of a bin of the given size covered
+// by the given rectangles, which is zero if the bin has no area
+func occupancy(rects []*Rectangle, width, height float64) float64 {
+	if width*height <= 0 {
+		return 0
+	}
+	total := 0.0
+	for _, r := range rects {
+		total += r.Area()
+	}
+	return total / (width * height)
+}
@@ -0,0 +1,89 @@
+package geo2
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func checkPacking(t *testing.T, result *PackResult, sizes []*Rectangle, w, h, padding float64) {
+	t.Helper()
+	for i, a := range result.Placements {
+		r := a.Rectangle
+		if r.X < 0 || r.Y < 0 || r.Right() > w || r.Bottom() > h {
+			t.Error("placed rectangles should be within the bin")
+		}
+		size := sizes[a.Index]
+		if (a.Rotated && (r.Width != size.Height || r.Height != size.Width)) ||
+			(!a.Rotated && (r.Width != size.Width || r.Height != size.Height)) {
+			t.Error("placed rectangles should keep their size")
+		}
+		for _, b := range result.Placements[i+1:] {
+			if a.Bin != b.Bin {
+				continue
+			}
+			shared := a.Rectangle.Clone().Expand(padding / 2).Intersection(b.Rectangle.Clone().Expand(padding / 2))
+			if nil != shared && shared.Area() > 0 {
+				t.Error("placed rectangles should not overlap")
+				return
+			}
+		}
+	}
+}
+
+func TestPackAlgorithms(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	sizes := make([]*Rectangle, 120)
+	for i := range sizes {
+		sizes[i] = NewRectangle(0, 0, float64(4+rng.Intn(40)), float64(4+rng.Intn(40)))
+	}
+	for _, algorithm := range []PackAlgorithm{PackMaxRects, PackSkyline, PackGuillotine} {
+		for _, heuristic := range []PackHeuristic{
+			PackBestShortSideFit, PackBestLongSideFit, PackBestAreaFit, PackTopLeft, PackContactPoint,
+		} {
+			opts := &PackOptions{algorithm, heuristic, true, 2, 0}
+			result := Pack(sizes, 256, 256, opts)
+			if len(result.Placements) != len(sizes) || len(result.Unplaced) != 0 {
+				t.Error("every rectangle should be packed")
+			}
+			checkPacking(t, result, sizes, 256, 256, 2)
+			for _, occupancy := range result.Occupancy {
+				if occupancy <= 0 || occupancy > 1 {
+					t.Error("occupancy should be a fraction of the bin")
+				}
+			}
+		}
+	}
+}
+
+func TestPackRotationAndLimits(t *testing.T) {
+	sizes := []*Rectangle{NewRectangle(0, 0, 10, 100), NewRectangle(0, 0, 100, 10)}
+	result := Pack(sizes, 100, 20, nil)
+	if len(result.Placements) != 1 || len(result.Occupancy) != 1 || result.Unplaced[0] != 0 {
+		t.Error("tall rectangle should not fit without rotation")
+	}
+	result = Pack(sizes, 100, 20, &PackOptions{AllowRotation: true})
+	if len(result.Placements) != 2 || !result.Placements[0].Rotated || result.Occupancy[0] != 1 {
+		t.Error("tall rectangle should be rotated to fill the bin")
+	}
+	sizes = []*Rectangle{NewRectangle(0, 0, 10, 10), NewRectangle(0, 0, 10, 10), NewRectangle(0, 0, 10, 10)}
+	result = Pack(sizes, 10, 10, &PackOptions{MaxBins: 2})
+	if len(result.Occupancy) != 2 || len(result.Unplaced) != 1 {
+		t.Error("packing should stop at the maximum number of bins")
+	}
+	result = Pack([]*Rectangle{NewRectangle(0, 0, 0, 0)}, 0, 0, nil)
+	if len(result.Occupancy) != 1 || result.Occupancy[0] != 0 {
+		t.Error("bin with no area should have no occupancy")
+	}
+}
+
+func TestMaxRectsBinPerfectFill(t *testing.T) {
+	bin := NewMaxRectsBin(4, 4, PackBestAreaFit, false)
+	for i := 0; i < 4; i++ {
+		if rect, _ := bin.Insert(2, 2); nil == rect {
+			t.Error("four quarters should fill the bin")
+		}
+	}
+	if rect, _ := bin.Insert(1, 1); nil != rect || bin.Occupancy() != 1 {
+		t.Error("full bin should not accept more rectangles")
+	}
+}
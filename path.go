@@ -1,6 +1,9 @@
 package geo2
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 // Path represents a string of connected lines
 // which may or may not represent a loop
@@ -36,11 +39,91 @@ func (path *Path) Clone() *Path {
 	return &clone
 }
 
+// Transform applies the given matrix to
+// every point of this path in place
+func (path *Path) Transform(m *Matrix3) {
+	for _, point := range *path {
+		point.MultiplyMatrix(m)
+	}
+}
+
+// Transformed returns a copy of this path
+// transformed by the given matrix
+func (path *Path) Transformed(m *Matrix3) Transformable {
+	clone := path.Clone()
+	clone.Transform(m)
+	return clone
+}
+
+// Bounds returns the smallest rectangle containing
+// every point of this path, or nil if the path is empty
+func (path *Path) Bounds() *Rectangle {
+	return NewRectangleFromPoints(*path)
+}
+
+// ConvexHull returns the convex hull of the points of this path,
+// wound counter clockwise (positive signed area) without any
+// collinear points. The points are copied into the hull
+func (path *Path) ConvexHull() *Path {
+	points := make([]*Vector, len(*path))
+	copy(points, *path)
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].X < points[j].X ||
+			(points[i].X == points[j].X && points[i].Y < points[j].Y)
+	})
+	if len(points) < 3 {
+		hull := Path{}
+		for i, p := range points {
+			if 0 == i || !p.Compare(points[i-1]) {
+				hull = append(hull, p.Clone())
+			}
+		}
+		return &hull
+	}
+
+	// andrew's monotone chain, building the lower then upper hull
+	hull := make([]*Vector, 0, len(points)*2)
+	for _, p := range points {
+		for len(hull) >= 2 && orient(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		p := points[i]
+		for len(hull) >= lower && orient(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	result := make(Path, len(hull)-1)
+	for i := range result {
+		result[i] = hull[i].Clone()
+	}
+	return &result
+}
+
 // Append appends the given point to the end of this path
 func (path *Path) Append(vec *Vector) {
 	(*path) = append(*path, vec)
 }
 
+// Contains returns true if the given point lies within the area of
+// this path, treated as a closed loop. Self-intersecting paths are
+// handled with the even-odd rule
+func (path *Path) Contains(point *Vector) bool {
+	inside := false
+	for i, a := range *path {
+		b := (*path)[(i+1)%len(*path)]
+		if (a.Y > point.Y) != (b.Y > point.Y) &&
+			point.X < a.X+(point.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
 // Triangulate triangulates this path into individual
 // triangles representing the area covered by this path
 //
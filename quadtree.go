@@ -0,0 +1,287 @@
+package geo2
+
+import "sync"
+
+// Quadtree is a spatial index of items keyed by their Rectangle
+// bounds. Each item is stored in the smallest node which fully
+// contains its bounds, so large items may be stored near the root.
+//
+// Items are used as map keys and so must be comparable (eg: pointers).
+// A Quadtree is safe for use by multiple concurrent readers along
+// with a single writer at a time
+type Quadtree struct {
+	mutex    sync.RWMutex
+	capacity int
+	maxDepth int
+	root     *quadNode
+	items    map[interface{}]*quadItem
+}
+
+type quadItem struct {
+	value  interface{}
+	bounds Rectangle
+	node   *quadNode
+}
+
+type quadNode struct {
+	bounds   Rectangle
+	depth    int
+	parent   *quadNode
+	children []*quadNode
+	items    []*quadItem
+	// total is the number of items in this node and its children
+	total int
+}
+
+// NewQuadtree creates an empty quadtree covering the given bounds.
+// Nodes are split once they hold more than capacity items, up to
+// maxDepth levels deep. Values of zero or less use the defaults of
+// 8 items and 8 levels. Items outside of the bounds are still
+// accepted but are stored in the root node
+func NewQuadtree(bounds *Rectangle, capacity, maxDepth int) *Quadtree {
+	if capacity <= 0 {
+		capacity = 8
+	}
+	if maxDepth <= 0 {
+		maxDepth = 8
+	}
+	return &Quadtree{
+		capacity: capacity,
+		maxDepth: maxDepth,
+		root:     &quadNode{bounds: *bounds.Clone().Normalize()},
+		items:    make(map[interface{}]*quadItem),
+	}
+}
+
+// Len returns the number of items in this quadtree
+func (qt *Quadtree) Len() int {
+	qt.mutex.RLock()
+	defer qt.mutex.RUnlock()
+	return len(qt.items)
+}
+
+// Insert adds the given item to this quadtree with the given bounds.
+// If the item is already in the tree its bounds are updated instead
+func (qt *Quadtree) Insert(item interface{}, bounds *Rectangle) {
+	qt.mutex.Lock()
+	defer qt.mutex.Unlock()
+	if existing, ok := qt.items[item]; ok {
+		qt.move(existing, bounds)
+		return
+	}
+	qi := &quadItem{value: item, bounds: *bounds.Clone().Normalize()}
+	qt.items[item] = qi
+	qt.insert(qt.root, qi)
+}
+
+// Remove removes the given item from this quadtree,
+// returning false if it was not in the tree
+func (qt *Quadtree) Remove(item interface{}) bool {
+	qt.mutex.Lock()
+	defer qt.mutex.Unlock()
+	qi, ok := qt.items[item]
+	if !ok {
+		return false
+	}
+	delete(qt.items, item)
+	qt.detach(qi)
+	return true
+}
+
+// Update moves the given item to its new bounds,
+// returning false if it was not in the tree
+func (qt *Quadtree) Update(item interface{}, bounds *Rectangle) bool {
+	qt.mutex.Lock()
+	defer qt.mutex.Unlock()
+	qi, ok := qt.items[item]
+	if !ok {
+		return false
+	}
+	qt.move(qi, bounds)
+	return true
+}
+
+// Query returns every item whose bounds overlap
+// or touch the given rectangle
+func (qt *Quadtree) Query(rect *Rectangle) []interface{} {
+	qt.mutex.RLock()
+	defer qt.mutex.RUnlock()
+	area := rect.Clone().Normalize()
+	var result []interface{}
+	stack := []*quadNode{qt.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, qi := range node.items {
+			if qi.bounds.Overlaps(area) {
+				result = append(result, qi.value)
+			}
+		}
+		for _, child := range node.children {
+			if child.bounds.Overlaps(area) {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return result
+}
+
+// QueryPoint returns every item whose bounds contain the given point
+func (qt *Quadtree) QueryPoint(vec *Vector) []interface{} {
+	qt.mutex.RLock()
+	defer qt.mutex.RUnlock()
+	var result []interface{}
+	stack := []*quadNode{qt.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, qi := range node.items {
+			if qi.bounds.Contains(vec) {
+				result = append(result, qi.value)
+			}
+		}
+		// points on the edge between children may
+		// be within items stored in either of them
+		for _, child := range node.children {
+			if child.bounds.Contains(vec) {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return result
+}
+
+// Bounds returns the bounds of the given item
+// in this quadtree, or nil if it is not present
+func (qt *Quadtree) Bounds(item interface{}) *Rectangle {
+	qt.mutex.RLock()
+	defer qt.mutex.RUnlock()
+	if qi, ok := qt.items[item]; ok {
+		return qi.bounds.Clone()
+	}
+	return nil
+}
+
+// move changes the bounds of the given item, only
+// relinking it if it no longer belongs in its node
+func (qt *Quadtree) move(qi *quadItem, bounds *Rectangle) {
+	qi.bounds = *bounds.Clone().Normalize()
+	node := qi.node
+	fits := node == qt.root || node.bounds.ContainsRectangle(&qi.bounds)
+	if fits && nil == node.childFor(&qi.bounds) {
+		return
+	}
+	qt.detach(qi)
+	qt.insert(qt.root, qi)
+}
+
+// insert places the given item in the smallest node
+// under the given node which contains its bounds
+func (qt *Quadtree) insert(node *quadNode, qi *quadItem) {
+	for {
+		child := node.childFor(&qi.bounds)
+		if nil == child {
+			break
+		}
+		node = child
+	}
+	qi.node = node
+	node.items = append(node.items, qi)
+	for n := node; nil != n; n = n.parent {
+		n.total++
+	}
+	if nil == node.children && len(node.items) > qt.capacity && node.depth < qt.maxDepth {
+		qt.split(node)
+	}
+}
+
+// split divides the given node into four children
+// and pushes down any items which fit within them
+func (qt *Quadtree) split(node *quadNode) {
+	w := node.bounds.Width * 0.5
+	h := node.bounds.Height * 0.5
+	x, y := node.bounds.X, node.bounds.Y
+	node.children = []*quadNode{
+		{bounds: Rectangle{x, y, w, h}},
+		{bounds: Rectangle{x + w, y, w, h}},
+		{bounds: Rectangle{x + w, y + h, w, h}},
+		{bounds: Rectangle{x, y + h, w, h}},
+	}
+	for _, child := range node.children {
+		child.depth = node.depth + 1
+		child.parent = node
+	}
+	items := node.items
+	node.items = nil
+	for _, qi := range items {
+		child := node.childFor(&qi.bounds)
+		if nil == child {
+			node.items = append(node.items, qi)
+			continue
+		}
+		qi.node = child
+		child.items = append(child.items, qi)
+		child.total++
+	}
+	for _, child := range node.children {
+		if child.total > qt.capacity && child.depth < qt.maxDepth {
+			qt.split(child)
+		}
+	}
+}
+
+// detach removes the given item from its node and collapses
+// any ancestors which no longer hold enough items to be split
+func (qt *Quadtree) detach(qi *quadItem) {
+	node := qi.node
+	for i, other := range node.items {
+		if other == qi {
+			last := len(node.items) - 1
+			node.items[i] = node.items[last]
+			node.items[last] = nil
+			node.items = node.items[:last]
+			break
+		}
+	}
+	qi.node = nil
+	for n := node; nil != n; n = n.parent {
+		n.total--
+	}
+
+	// collapse the highest ancestor which no longer
+	// holds enough items to need splitting
+	var collapse *quadNode
+	for n := node; nil != n; n = n.parent {
+		if nil != n.children && n.total <= qt.capacity {
+			collapse = n
+		}
+	}
+	if nil != collapse {
+		node = collapse
+		node.items = node.collect(node.items[:0:0])
+		for _, item := range node.items {
+			item.node = node
+		}
+		node.children = nil
+	}
+}
+
+// childFor returns the child of this node which fully
+// contains the given bounds, or nil if there is none
+func (node *quadNode) childFor(bounds *Rectangle) *quadNode {
+	for _, child := range node.children {
+		if child.bounds.ContainsRectangle(bounds) {
+			return child
+		}
+	}
+	return nil
+}
+
+// collect appends all items in this node and its children to items
+func (node *quadNode) collect(items []*quadItem) []*quadItem {
+	items = append(items, node.items...)
+	for _, child := range node.children {
+		items = child.collect(items)
+	}
+	return items
+}
@@ -0,0 +1,132 @@
+package geo2
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func sortedInts(values []interface{}) []int {
+	result := make([]int, len(values))
+	for i, v := range values {
+		result[i] = v.(int)
+	}
+	sort.Ints(result)
+	return result
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQuadtreeQuery(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	qt := NewQuadtree(NewRectangle(0, 0, 1000, 1000), 4, 6)
+	bounds := make([]*Rectangle, 500)
+	for i := range bounds {
+		bounds[i] = NewRectangle(rng.Float64()*980, rng.Float64()*980, rng.Float64()*20, rng.Float64()*20)
+		qt.Insert(i, bounds[i])
+	}
+	if qt.Len() != len(bounds) {
+		t.Error("quadtree should hold every inserted item")
+	}
+
+	area := NewRectangle(200, 300, 150, 100)
+	var expected []int
+	for i, b := range bounds {
+		if b.Overlaps(area) {
+			expected = append(expected, i)
+		}
+	}
+	if !equalInts(sortedInts(qt.Query(area)), expected) {
+		t.Error("query should match a linear scan")
+	}
+
+	point := NewVector(500, 500)
+	expected = nil
+	for i, b := range bounds {
+		if b.Contains(point) {
+			expected = append(expected, i)
+		}
+	}
+	if !equalInts(sortedInts(qt.QueryPoint(point)), expected) {
+		t.Error("point query should match a linear scan")
+	}
+}
+
+func TestQuadtreeQueryPointOnSplit(t *testing.T) {
+	qt := NewQuadtree(NewRectangle(0, 0, 100, 100), 1, 4)
+	qt.Insert(0, NewRectangle(10, 10, 5, 5))
+	qt.Insert(1, NewRectangle(50, 10, 5, 5))
+	qt.Insert(2, NewRectangle(60, 60, 5, 5))
+	if !equalInts(sortedInts(qt.QueryPoint(NewVector(50, 12))), []int{1}) {
+		t.Error("point on the split between children should find items in either")
+	}
+}
+
+func TestQuadtreeRemoveUpdate(t *testing.T) {
+	qt := NewQuadtree(NewRectangle(0, 0, 100, 100), 2, 4)
+	for i := 0; i < 20; i++ {
+		qt.Insert(i, NewRectangle(float64(i*5), float64(i*5), 1, 1))
+	}
+	if !qt.Remove(3) || qt.Remove(3) {
+		t.Error("items should only be removed once")
+	}
+	if len(qt.QueryPoint(NewVector(15.5, 15.5))) != 0 {
+		t.Error("removed items should not be found")
+	}
+	if !qt.Update(4, NewRectangle(90, 10, 2, 2)) {
+		t.Error("existing items should be updated")
+	}
+	if len(qt.QueryPoint(NewVector(20.5, 20.5))) != 0 {
+		t.Error("moved items should not be found at their old position")
+	}
+	if found := qt.QueryPoint(NewVector(91, 11)); len(found) != 1 || found[0] != 4 {
+		t.Error("moved items should be found at their new position")
+	}
+	for i := 0; i < 20; i++ {
+		qt.Remove(i)
+	}
+	if qt.Len() != 0 || len(qt.Query(NewRectangle(0, 0, 100, 100))) != 0 {
+		t.Error("quadtree should be empty after removing everything")
+	}
+}
+
+func TestQuadtreeOutsideBounds(t *testing.T) {
+	qt := NewQuadtree(NewRectangle(0, 0, 10, 10), 1, 4)
+	qt.Insert("far", NewRectangle(50, 50, 1, 1))
+	qt.Insert("near", NewRectangle(1, 1, 1, 1))
+	if found := qt.Query(NewRectangle(40, 40, 20, 20)); len(found) != 1 || found[0] != "far" {
+		t.Error("items outside of the bounds should still be found")
+	}
+}
+
+func TestQuadtreeConcurrentReaders(t *testing.T) {
+	qt := NewQuadtree(NewRectangle(0, 0, 100, 100), 4, 6)
+	for i := 0; i < 100; i++ {
+		qt.Insert(i, NewRectangle(float64(i%10)*10, float64(i/10)*10, 5, 5))
+	}
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if len(qt.QueryPoint(NewVector(float64(i%10)*10+1, float64(i/10)*10+1))) != 1 {
+					t.Error("concurrent readers should find each item")
+				}
+			}
+		}()
+	}
+	qt.Update(0, NewRectangle(0, 0, 5, 5))
+	wg.Wait()
+}
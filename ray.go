@@ -0,0 +1,220 @@
+package geo2
+
+import (
+	"math"
+	"sort"
+)
+
+// Ray represents a half line starting at
+// an origin and heading in a direction
+type Ray struct {
+	Origin    *Vector
+	Direction *Vector
+}
+
+// NewRay creates a new ray from the given origin and direction
+// (which does not need to be normalized)
+func NewRay(origin, direction *Vector) *Ray {
+	return &Ray{origin, direction}
+}
+
+// RayHit describes where a ray hits a shape
+type RayHit struct {
+	// Distance is how far along the ray the hit is
+	Distance float64
+	// Point is the position of the hit
+	Point *Vector
+	// Normal is the unit surface normal at the hit, facing
+	// back towards the ray (against its direction)
+	Normal *Vector
+	// Index is the edge (or triangle for a TriangleList) which
+	// was hit, or -1 for shapes without edges such as circles
+	Index int
+	// Shape is the index of the shape which was hit
+	// when casting against several shapes at once
+	Shape int
+}
+
+// Raycastable is implemented by shapes which can be hit by a Ray
+type Raycastable interface {
+	// RaycastAll returns every hit of the given ray
+	// against this shape, sorted by distance
+	RaycastAll(ray *Ray) []*RayHit
+}
+
+// PointAt returns the position at the given distance along this ray
+func (ray *Ray) PointAt(distance float64) *Vector {
+	return ray.unit().MultiplyScalar(distance).Add(ray.Origin)
+}
+
+// Cast returns the first hit of this ray against
+// the given shape, or nil if it is missed
+func (ray *Ray) Cast(shape Raycastable) *RayHit {
+	hits := shape.RaycastAll(ray)
+	if 0 == len(hits) {
+		return nil
+	}
+	return hits[0]
+}
+
+// CastAll returns every hit of this ray against all of the given
+// shapes sorted by distance, with each hit recording which shape
+// it belongs to
+func (ray *Ray) CastAll(shapes ...Raycastable) []*RayHit {
+	var hits []*RayHit
+	for i, shape := range shapes {
+		for _, hit := range shape.RaycastAll(ray) {
+			hit.Shape = i
+			hits = append(hits, hit)
+		}
+	}
+	sortHits(hits)
+	return hits
+}
+
+// CastFirst returns the closest hit of this ray against
+// all of the given shapes, or nil if they are all missed
+func (ray *Ray) CastFirst(shapes ...Raycastable) *RayHit {
+	var best *RayHit
+	for i, shape := range shapes {
+		if hit := ray.Cast(shape); nil != hit && (nil == best || hit.Distance < best.Distance) {
+			hit.Shape = i
+			best = hit
+		}
+	}
+	return best
+}
+
+// unit returns the normalized direction of this ray
+func (ray *Ray) unit() *Vector {
+	dir := ray.Direction.Clone()
+	if 0 == dir.LengthSqd() {
+		return dir
+	}
+	return dir.Normalize()
+}
+
+// castEdges casts this ray against each of the given edges
+func (ray *Ray) castEdges(edges []*Line) []*RayHit {
+	dir := ray.unit()
+	if 0 == dir.LengthSqd() {
+		return nil
+	}
+	var hits []*RayHit
+	for i, edge := range edges {
+		d, ok := raySegment(ray.Origin, dir, edge.A, edge.B)
+		if !ok {
+			continue
+		}
+		normal := edgeNormal(edge.A, edge.B).Normalize()
+		if normal.Dot(dir) > 0 {
+			normal.Negate()
+		}
+		hits = append(hits, &RayHit{
+			Distance: d,
+			Point:    dir.Clone().MultiplyScalar(d).Add(ray.Origin),
+			Normal:   normal,
+			Index:    i,
+		})
+	}
+	sortHits(hits)
+	return hits
+}
+
+// RaycastAll returns the hit of the given ray against this line
+func (line *Line) RaycastAll(ray *Ray) []*RayHit {
+	return ray.castEdges([]*Line{line})
+}
+
+// RaycastAll returns every hit of the given ray against the edges
+// of this rectangle (indexed in the same order as Edges)
+func (rect *Rectangle) RaycastAll(ray *Ray) []*RayHit {
+	return ray.castEdges(rect.Edges())
+}
+
+// RaycastAll returns every hit of the given ray against
+// the edges of this triangle (edge i runs from point i)
+func (t *Triangle) RaycastAll(ray *Ray) []*RayHit {
+	return ray.castEdges(pathEdges(t.ToPath(), true))
+}
+
+// RaycastAll returns every hit of the given ray against the edges of
+// this path, which is treated as a closed loop (edge i runs from point i)
+func (path *Path) RaycastAll(ray *Ray) []*RayHit {
+	return ray.castEdges(pathEdges(path, true))
+}
+
+// RaycastAll returns every hit of the given ray against the
+// edges of every triangle in this list, with the index of the
+// triangle hit (triangles sharing an edge are both hit)
+func (tris *TriangleList) RaycastAll(ray *Ray) []*RayHit {
+	var hits []*RayHit
+	for i, tri := range *tris {
+		for _, hit := range tri.RaycastAll(ray) {
+			hit.Index = i
+			hits = append(hits, hit)
+		}
+	}
+	sortHits(hits)
+	return hits
+}
+
+// RaycastAll returns the points where the given
+// ray enters and exits this circle
+func (c *Circle) RaycastAll(ray *Ray) []*RayHit {
+	dir := ray.unit()
+	if 0 == dir.LengthSqd() {
+		return nil
+	}
+	m := ray.Origin.Clone().Sub(c.Center)
+	b := m.Dot(dir)
+	disc := b*b - (m.LengthSqd() - c.Radius*c.Radius)
+	if disc < 0 {
+		return nil
+	}
+	root := math.Sqrt(disc)
+	var hits []*RayHit
+	for _, d := range []float64{-b - root, -b + root} {
+		if d < 0 || (len(hits) > 0 && d == hits[0].Distance) {
+			continue
+		}
+		point := dir.Clone().MultiplyScalar(d).Add(ray.Origin)
+		normal := point.Clone().Sub(c.Center)
+		if 0 == normal.LengthSqd() {
+			normal = dir.Clone()
+		}
+		normal.Normalize()
+		if normal.Dot(dir) > 0 {
+			normal.Negate()
+		}
+		hits = append(hits, &RayHit{Distance: d, Point: point, Normal: normal, Index: -1})
+	}
+	return hits
+}
+
+// Cast returns the first hit of the given ray against the triangles in
+// this hierarchy, or nil if it misses them all. Unlike casting against
+// the TriangleList itself, a ray starting inside a triangle hits it
+// immediately (with a normal facing back along the ray)
+func (bvh *BVH) Cast(ray *Ray) *RayHit {
+	index, dist := bvh.Raycast(ray.Origin, ray.Direction)
+	if index < 0 {
+		return nil
+	}
+	dir := ray.unit()
+	hit := &RayHit{Distance: dist, Point: ray.PointAt(dist), Normal: dir.Clone().Negate(), Index: index}
+	if dist > 0 {
+		// find the edge which was hit to report its normal
+		for _, edgeHit := range (*bvh.triangles)[index].RaycastAll(ray) {
+			hit.Normal = edgeHit.Normal
+			break
+		}
+	}
+	return hit
+}
+
+func sortHits(hits []*RayHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Distance < hits[j].Distance
+	})
+}
@@ -0,0 +1,70 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRayCast(t *testing.T) {
+	ray := NewRay(NewVector(0, 1), NewVector(2, 0))
+
+	hit := ray.Cast(NewRectangle(3, 0, 2, 2))
+	if nil == hit || math.Abs(hit.Distance-3) > 1e-9 ||
+		!hit.Point.CloseEnough(NewVector(3, 1), 1e-9) ||
+		!hit.Normal.CloseEnough(NewVector(-1, 0), 1e-9) {
+		t.Error("ray should hit the near side of the rectangle")
+	}
+
+	hit = ray.Cast(NewCircle(NewVector(10, 1), 2))
+	if nil == hit || math.Abs(hit.Distance-8) > 1e-9 || -1 != hit.Index ||
+		!hit.Normal.CloseEnough(NewVector(-1, 0), 1e-9) {
+		t.Error("ray should hit the near side of the circle")
+	}
+
+	if nil != ray.Cast(NewLine(NewVector(-1, 0), NewVector(-1, 2))) {
+		t.Error("line behind the ray should not be hit")
+	}
+
+	inside := NewRay(NewVector(10, 1), NewVector(0, 1))
+	hit = inside.Cast(NewCircle(NewVector(10, 1), 2))
+	if nil == hit || math.Abs(hit.Distance-2) > 1e-9 ||
+		!hit.Normal.CloseEnough(NewVector(0, -1), 1e-9) {
+		t.Error("ray from inside should exit the circle facing back")
+	}
+}
+
+func TestRayCastAll(t *testing.T) {
+	ray := NewRay(NewVector(0, 0.5), NewVector(1, 0))
+	wall := NewLine(NewVector(8, -5), NewVector(8, 5))
+	box := NewRectangle(2, 0, 2, 1)
+	hits := ray.CastAll(wall, box)
+	if 3 != len(hits) {
+		t.Fatal("ray should hit the box twice and the wall once")
+	}
+	expected := []struct {
+		distance float64
+		shape    int
+	}{{2, 1}, {4, 1}, {8, 0}}
+	for i, e := range expected {
+		if math.Abs(hits[i].Distance-e.distance) > 1e-9 || e.shape != hits[i].Shape {
+			t.Error("hits should be sorted by distance and record their shape")
+		}
+	}
+	if first := ray.CastFirst(wall, box); nil == first || 1 != first.Shape {
+		t.Error("box should be hit first")
+	}
+}
+
+func TestRayCastTriangles(t *testing.T) {
+	tris := gridTriangles(4)
+	ray := NewRay(NewVector(-1, 0.25), NewVector(1, 0))
+	hits := tris.RaycastAll(ray)
+	if 0 == len(hits) || math.Abs(hits[0].Distance-1) > 1e-9 {
+		t.Fatal("ray should enter the grid at distance 1")
+	}
+	hit := NewBVH(tris, BVHSplitSAH).Cast(ray)
+	if nil == hit || hit.Index != hits[0].Index || math.Abs(hit.Distance-1) > 1e-9 ||
+		!hit.Normal.CloseEnough(NewVector(-1, 0), 1e-9) {
+		t.Error("bvh cast should match the first hit on the list")
+	}
+}
@@ -15,6 +15,11 @@ func NewRectangle(x, y, w, h float64) *Rectangle {
   return &Rectangle{x, y, w, h}
 }
 
+// Clone creates a copy of this rectangle
+func (rect *Rectangle) Clone() *Rectangle {
+  return NewRectangle(rect.X, rect.Y, rect.Width, rect.Height)
+}
+
 // Set sets the dimensions of this rectangle
 func (rect *Rectangle) Set(x, y, w, h float64) {
   rect.X = x
@@ -81,13 +86,181 @@ func (rect *Rectangle) Contains(vec *Vector) bool {
 // DistanceTo calculates the closest distance from the edges
 // of this rectangle to the given point
 func (rect *Rectangle) DistanceTo(vec *Vector) float64 {
-  var min float64
-  for _, edge := range rect.Edges() {
-    min = math.Min(
-      min,
-      edge.DistanceToPoint(vec, true),
-    )
+  return math.Abs(rect.SignedDistanceTo(vec))
+}
+
+// SignedDistanceTo calculates the closest distance from the edges
+// of this rectangle to the given point, which is negative if the
+// point is inside of this rectangle
+func (rect *Rectangle) SignedDistanceTo(vec *Vector) float64 {
+  r := rect.Clone().Normalize()
+  dx := math.Max(r.X-vec.X, vec.X-r.Right())
+  dy := math.Max(r.Y-vec.Y, vec.Y-r.Bottom())
+  if dx <= 0 && dy <= 0 {
+    return math.Max(dx, dy)
+  }
+  return math.Hypot(math.Max(dx, 0), math.Max(dy, 0))
+}
+
+// NewRectangleFromPoints creates the smallest rectangle which
+// contains all of the given points, or nil if there are no points
+func NewRectangleFromPoints(points []*Vector) *Rectangle {
+  if 0 == len(points) {
+    return nil
+  }
+  minX, minY := points[0].X, points[0].Y
+  maxX, maxY := minX, minY
+  for _, p := range points[1:] {
+    minX = math.Min(minX, p.X)
+    minY = math.Min(minY, p.Y)
+    maxX = math.Max(maxX, p.X)
+    maxY = math.Max(maxY, p.Y)
+  }
+  return NewRectangle(minX, minY, maxX-minX, maxY-minY)
+}
+
+// Right returns the x position of the right edge of this rectangle
+func (rect *Rectangle) Right() float64 {
+  return rect.X + rect.Width
+}
+
+// Bottom returns the y position of the bottom edge of this rectangle
+func (rect *Rectangle) Bottom() float64 {
+  return rect.Y + rect.Height
+}
+
+// Area returns the area of this rectangle
+func (rect *Rectangle) Area() float64 {
+  return math.Abs(rect.Width * rect.Height)
+}
+
+// Corners returns the corners of this rectangle
+// (starting at the top left and going clockwise)
+func (rect *Rectangle) Corners() []*Vector {
+  return *rect.ToPath()
+}
+
+// Normalize flips any negative width or height of this
+// rectangle so that it covers the same area with a positive size
+func (rect *Rectangle) Normalize() *Rectangle {
+  if rect.Width < 0 {
+    rect.X += rect.Width
+    rect.Width = -rect.Width
+  }
+  if rect.Height < 0 {
+    rect.Y += rect.Height
+    rect.Height = -rect.Height
   }
+  return rect
+}
+
+// Expand grows this rectangle by the given margin on every side
+// (or shrinks it for a negative margin, to a minimum size of zero)
+func (rect *Rectangle) Expand(margin float64) *Rectangle {
+  rect.Normalize()
+  center := rect.Center()
+  rect.Width = math.Max(0, rect.Width+margin*2)
+  rect.Height = math.Max(0, rect.Height+margin*2)
+  rect.X = center.X - rect.Width*0.5
+  rect.Y = center.Y - rect.Height*0.5
+  return rect
+}
+
+// Union returns the smallest rectangle which
+// contains both this rectangle and the given one
+func (rect *Rectangle) Union(rect2 *Rectangle) *Rectangle {
+  a := rect.Clone().Normalize()
+  b := rect2.Clone().Normalize()
+  x := math.Min(a.X, b.X)
+  y := math.Min(a.Y, b.Y)
+  return NewRectangle(
+    x, y,
+    math.Max(a.Right(), b.Right())-x,
+    math.Max(a.Bottom(), b.Bottom())-y,
+  )
+}
+
+// Intersection returns the area shared by this rectangle and
+// the given one, or nil if the rectangles do not overlap. Rectangles
+// which only touch produce a rectangle with zero width or height
+func (rect *Rectangle) Intersection(rect2 *Rectangle) *Rectangle {
+  a := rect.Clone().Normalize()
+  b := rect2.Clone().Normalize()
+  x := math.Max(a.X, b.X)
+  y := math.Max(a.Y, b.Y)
+  right := math.Min(a.Right(), b.Right())
+  bottom := math.Min(a.Bottom(), b.Bottom())
+  if right < x || bottom < y {
+    return nil
+  }
+  return NewRectangle(x, y, right-x, bottom-y)
+}
+
+// Overlaps returns true if this rectangle and the
+// given one overlap or touch
+func (rect *Rectangle) Overlaps(rect2 *Rectangle) bool {
+  a := rect.Clone().Normalize()
+  b := rect2.Clone().Normalize()
+  return (a.X <= b.Right() &&
+    b.X <= a.Right() &&
+    a.Y <= b.Bottom() &&
+    b.Y <= a.Bottom())
+}
+
+// ContainsRectangle returns true if the given rectangle
+// is entirely within this rectangle
+func (rect *Rectangle) ContainsRectangle(rect2 *Rectangle) bool {
+  a := rect.Clone().Normalize()
+  b := rect2.Clone().Normalize()
+  return (b.X >= a.X &&
+    b.Right() <= a.Right() &&
+    b.Y >= a.Y &&
+    b.Bottom() <= a.Bottom())
+}
+
+// ToPath returns the corners of this rectangle as a closed
+// path (starting at the top left and going clockwise)
+func (rect *Rectangle) ToPath() *Path {
+  bottom := rect.Y + rect.Height
+  right := rect.X + rect.Width
+  return &Path{
+    NewVector(rect.X, rect.Y),
+    NewVector(right, rect.Y),
+    NewVector(right, bottom),
+    NewVector(rect.X, bottom),
+  }
+}
+
+// Transform applies the given matrix to this rectangle in
+// place. Since a rectangle is always axis aligned, the result
+// is the bounds of the transformed corners (see TransformToPath
+// to preserve the orientation of the corners instead)
+func (rect *Rectangle) Transform(m *Matrix3) {
+  corners := rect.TransformToPath(m)
+  minX, minY := math.Inf(1), math.Inf(1)
+  maxX, maxY := math.Inf(-1), math.Inf(-1)
+  for _, corner := range *corners {
+    minX = math.Min(minX, corner.X)
+    minY = math.Min(minY, corner.Y)
+    maxX = math.Max(maxX, corner.X)
+    maxY = math.Max(maxY, corner.Y)
+  }
+  rect.Set(minX, minY, maxX-minX, maxY-minY)
+}
+
+// Transformed returns the bounds of this rectangle
+// once transformed by the given matrix
+func (rect *Rectangle) Transformed(m *Matrix3) Transformable {
+  clone := rect.Clone()
+  clone.Transform(m)
+  return clone
+}
 
-  return min
+// TransformToPath returns the corners of this rectangle
+// transformed by the given matrix, which may no longer be
+// axis aligned (see ToPath for the order of the corners)
+func (rect *Rectangle) TransformToPath(m *Matrix3) *Path {
+  path := rect.ToPath()
+  path.Transform(m)
+  return path
 }
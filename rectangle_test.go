@@ -0,0 +1,76 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRectangleDistance(t *testing.T) {
+	rect := NewRectangle(0, 0, 4, 2)
+	if 3 != rect.DistanceTo(NewVector(7, 1)) {
+		t.Error("distance to a point beside the rectangle should be to the edge")
+	}
+	if 5 != rect.DistanceTo(NewVector(7, 6)) {
+		t.Error("distance to a point off the corner should be to the corner")
+	}
+	if -0.5 != rect.SignedDistanceTo(NewVector(2, 1.5)) {
+		t.Error("signed distance should be negative inside the rectangle")
+	}
+	if 0.5 != rect.DistanceTo(NewVector(2, 1.5)) {
+		t.Error("distance from inside should be to the closest edge")
+	}
+}
+
+func TestRectangleUnionIntersection(t *testing.T) {
+	a := NewRectangle(0, 0, 4, 4)
+	b := NewRectangle(2, 3, 4, 4)
+	if *a.Union(b) != *NewRectangle(0, 0, 6, 7) {
+		t.Error("union should contain both rectangles")
+	}
+	if *a.Intersection(b) != *NewRectangle(2, 3, 2, 1) {
+		t.Error("intersection should be the shared area")
+	}
+	if nil != a.Intersection(NewRectangle(5, 5, 1, 1)) {
+		t.Error("separate rectangles should not intersect")
+	}
+	if !a.Overlaps(b) || a.Overlaps(NewRectangle(-2, 5, 1, 1)) {
+		t.Error("overlap test should match the intersection")
+	}
+	if !a.ContainsRectangle(NewRectangle(1, 1, 2, 3)) || a.ContainsRectangle(b) {
+		t.Error("rectangle containment should require the whole rectangle")
+	}
+}
+
+func TestRectangleNormalizeExpand(t *testing.T) {
+	rect := NewRectangle(4, 4, -2, -3).Normalize()
+	if *rect != *NewRectangle(2, 1, 2, 3) {
+		t.Error("normalize should flip negative sizes")
+	}
+	if *rect.Expand(1) != *NewRectangle(1, 0, 4, 5) {
+		t.Error("expand should grow each side by the margin")
+	}
+	if rect.Expand(-3).Area() != 0 {
+		t.Error("shrinking should not produce a negative size")
+	}
+}
+
+func TestRectangleFromPoints(t *testing.T) {
+	path := &Path{NewVector(1, 5), NewVector(-2, 3), NewVector(4, -1)}
+	if *path.Bounds() != *NewRectangle(-2, -1, 6, 6) {
+		t.Error("path bounds should contain every point")
+	}
+	tris := path.Triangulate()
+	if *tris.Bounds() != *path.Bounds() {
+		t.Error("triangle bounds should match the path bounds")
+	}
+	if nil != (&Path{}).Bounds() {
+		t.Error("empty path should have no bounds")
+	}
+	corners := NewRectangle(0, 0, 2, 1).Corners()
+	if len(corners) != 4 || !corners[2].Compare(NewVector(2, 1)) {
+		t.Error("corners should go clockwise from the top left")
+	}
+	if math.Abs(NewRectangle(0, 0, -2, 3).Area()-6) > 0 {
+		t.Error("area should be positive for negative sizes")
+	}
+}
@@ -0,0 +1,261 @@
+package geo2
+
+import "math"
+
+// RefineOptions controls the quality of a refined mesh
+type RefineOptions struct {
+	// MinAngle is the smallest angle in radians allowed in any
+	// triangle (defaults to 20°). Angles above about 33° may
+	// prevent refinement from finishing before MaxPoints is reached
+	MinAngle float64
+	// MaxArea is the largest area allowed for any
+	// triangle, with zero meaning there is no limit
+	MaxArea float64
+	// MaxPoints limits how many points can be added to
+	// the mesh during refinement (defaults to 10000)
+	MaxPoints int
+}
+
+// Refine triangulates the area within this path (treated as a closed
+// loop) and outside of the given holes using Ruppert's Delaunay
+// refinement. Points are added to the boundary and interior until every
+// triangle meets the minimum angle and maximum area of the options, or
+// the maximum number of points has been added. Corners sharper than
+// 60° are handled as Shewchuk's Triangle does, so the small angles
+// they force near the corner are kept rather than refined forever.
+// Boundary edges may be split but are never crossed by a triangle.
+// Triangles in the result share their points. Returns nil if the path
+// has fewer than three distinct points, and an empty list if the area
+// it encloses is zero
+func (path *Path) Refine(holes []*Path, opts *RefineOptions) *TriangleList {
+	loops := skeletonLoops(path, holes)
+	if nil == loops {
+		return nil
+	}
+	minAngle := math.Pi / 9
+	maxArea := 0.0
+	maxPoints := 10000
+	if nil != opts {
+		if opts.MinAngle > 0 {
+			minAngle = opts.MinAngle
+		}
+		maxArea = opts.MaxArea
+		if opts.MaxPoints > 0 {
+			maxPoints = opts.MaxPoints
+		}
+	}
+	// triangles with a larger circumradius to shortest edge
+	// ratio than this have an angle smaller than the minimum
+	maxRatio := 1 / (2 * math.Sin(minAngle))
+
+	bounds := NewRectangleFromPoints(loops[0])
+	// points are never placed closer than this to one another
+	tolerance := 1e-9 * (1 + math.Max(bounds.Width, bounds.Height))
+	mesh := newDelaunayMesh(bounds)
+	// segments are the pieces of the input edges (whose index is
+	// kept in origin), and lies holds the input edges each point of
+	// the boundary sits on. Corners of less than 60° are sharp
+	var segments, inputs [][2]int
+	var origin []int
+	lies := make(map[int][]int)
+	sharp := make(map[int]bool)
+	for _, loop := range loops {
+		indices := make([]int, len(loop))
+		for i, p := range loop {
+			indices[i] = mesh.insert(p.Clone())
+		}
+		for i, p := range loop {
+			a, b := indices[i], indices[(i+1)%len(loop)]
+			lies[a] = append(lies[a], len(inputs))
+			lies[b] = append(lies[b], len(inputs))
+			segments = append(segments, [2]int{a, b})
+			origin = append(origin, len(inputs))
+			inputs = append(inputs, [2]int{a, b})
+
+			// the area being meshed is always to the left of each loop
+			out := loop[(i+1)%len(loop)].Clone().Sub(p)
+			back := loop[(i+len(loop)-1)%len(loop)].Clone().Sub(p)
+			if angle := math.Atan2(out.Cross(back), out.Dot(back)); angle > 0 && angle < math.Pi/3 {
+				sharp[indices[i]] = true
+			}
+		}
+	}
+	inside := func(point *Vector) bool {
+		outer := Path(loops[0])
+		if !outer.Contains(point) {
+			return false
+		}
+		for _, hole := range loops[1:] {
+			if loop := Path(hole); loop.Contains(point) {
+				return false
+			}
+		}
+		return true
+	}
+	farFromPoints := func(point *Vector) bool {
+		for _, p := range mesh.points[3:] {
+			if p.Clone().Sub(point).LengthSqd() < tolerance*tolerance {
+				return false
+			}
+		}
+		return true
+	}
+	// clustered returns true if the points lie on two input
+	// edges which meet at a sharp corner, where triangles between
+	// them can never be improved by adding more points
+	clustered := func(u, w int) bool {
+		for _, first := range lies[u] {
+			for _, second := range lies[w] {
+				if first == second {
+					continue
+				}
+				for _, v := range inputs[first] {
+					if sharp[v] && (v == inputs[second][0] || v == inputs[second][1]) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	added := 0
+	// encroaches returns true if the point lies within the
+	// diametral circle of the given boundary edge
+	encroaches := func(point *Vector, s int) bool {
+		a, b := mesh.points[segments[s][0]], mesh.points[segments[s][1]]
+		if point.Compare(a) || point.Compare(b) {
+			return false
+		}
+		middle := a.Clone().Add(b).MultiplyScalar(0.5)
+		return middle.Sub(point).LengthSqd() < a.Clone().Sub(b).LengthSqd()/4
+	}
+	// boundary edges which may be encroached are queued to be
+	// checked, as an edge with no points inside its diametral
+	// circle is certain to be part of the Delaunay mesh
+	var queue []int
+	inserted := func(point *Vector) {
+		for s := range segments {
+			if encroaches(point, s) {
+				queue = append(queue, s)
+			}
+		}
+	}
+	// splitSegment splits the given boundary edge, returning
+	// false if the new point would be too close to another
+	splitSegment := func(s int) bool {
+		a, b := segments[s][0], segments[s][1]
+		pa, pb := mesh.points[a], mesh.points[b]
+		// edges leaving a sharp corner are split on circles around
+		// it whose radii are powers of two, so that the points on
+		// either side line up rather than encroaching on each other
+		along := 0.5
+		length := pb.Clone().Sub(pa).Length()
+		shell := math.Pow(2, math.Round(math.Log2(length/2))) / length
+		switch {
+		case sharp[a] && !sharp[b]:
+			along = shell
+		case sharp[b] && !sharp[a]:
+			along = 1 - shell
+		}
+		point := pb.Clone().Sub(pa).MultiplyScalar(along).Add(pa)
+		if !farFromPoints(point) {
+			return false
+		}
+		m := mesh.insert(point)
+		lies[m] = []int{origin[s]}
+		segments[s] = [2]int{a, m}
+		segments = append(segments, [2]int{m, b})
+		origin = append(origin, origin[s])
+		queue = append(queue, s, len(segments)-1)
+		inserted(point)
+		added++
+		return true
+	}
+	conform := func() {
+		for len(queue) > 0 && added < maxPoints {
+			s := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			for _, p := range mesh.points[3:] {
+				if encroaches(p, s) {
+					splitSegment(s)
+					break
+				}
+			}
+		}
+	}
+
+	for s := range segments {
+		queue = append(queue, s)
+	}
+	conform()
+	// triangles which could not be improved are skipped
+	// until they are replaced by inserting another point
+	skipped := make(map[*delaunayTriangle]bool)
+	for added < maxPoints {
+		var bad *delaunayTriangle
+		for _, tri := range mesh.triangles {
+			if skipped[tri] || tri.v[0] < 3 || tri.v[1] < 3 || tri.v[2] < 3 {
+				continue
+			}
+			t := NewTriangle([]*Vector{mesh.points[tri.v[0]], mesh.points[tri.v[1]], mesh.points[tri.v[2]]})
+			if !inside(t.Centroid()) {
+				continue
+			}
+			if maxArea > 0 && t.Area() > maxArea {
+				bad = tri
+				break
+			}
+			if t.RadiusEdgeRatio() <= maxRatio {
+				continue
+			}
+			// small angles opposite an edge across a sharp
+			// corner come from the input, so are left alone
+			lengths := t.EdgeLengths()
+			shortest := 0
+			for i := range lengths {
+				if lengths[i] < lengths[shortest] {
+					shortest = i
+				}
+			}
+			if !clustered(tri.v[shortest], tri.v[(shortest+1)%3]) {
+				bad = tri
+				break
+			}
+		}
+		if nil == bad {
+			break
+		}
+		// circumcenters which would encroach on the boundary split it
+		// instead, which also keeps new points inside the polygon
+		placed, encroached := false, false
+		for s := range segments {
+			if encroaches(bad.center, s) {
+				placed, encroached = splitSegment(s), true
+				break
+			}
+		}
+		if center := bad.center.Clone(); !encroached && inside(center) && farFromPoints(center) {
+			mesh.insert(center)
+			inserted(center)
+			added++
+			placed = true
+		}
+		if !placed {
+			skipped[bad] = true
+		}
+		conform()
+	}
+
+	triangles := TriangleList{}
+	for _, tri := range mesh.triangles {
+		if tri.v[0] < 3 || tri.v[1] < 3 || tri.v[2] < 3 {
+			continue
+		}
+		t := NewTriangle([]*Vector{mesh.points[tri.v[0]], mesh.points[tri.v[1]], mesh.points[tri.v[2]]})
+		if inside(t.Centroid()) {
+			triangles = append(triangles, t)
+		}
+	}
+	return &triangles
+}
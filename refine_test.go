@@ -0,0 +1,89 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func checkRefined(t *testing.T, tris *TriangleList, area, minAngle, maxArea float64) {
+	t.Helper()
+	if nil == tris || 0 == len(*tris) {
+		t.Error("expected triangles")
+		return
+	}
+	total := 0.0
+	for _, tri := range *tris {
+		if tri.MinAngle() < minAngle-1e-9 {
+			t.Error("triangles should meet the minimum angle")
+		}
+		if maxArea > 0 && tri.Area() > maxArea+1e-9 {
+			t.Error("triangles should meet the maximum area")
+		}
+		total += tri.Area()
+	}
+	if math.Abs(total-area) > 1e-9 {
+		t.Error("triangles should cover the area")
+	}
+}
+
+func TestRefine(t *testing.T) {
+	minAngle := 25 * math.Pi / 180
+	opts := &RefineOptions{MinAngle: minAngle, MaxArea: 2}
+	square := NewRectangle(0, 0, 10, 10).ToPath()
+	checkRefined(t, square.Refine(nil, opts), 100, minAngle, 2)
+
+	ell := &Path{NewVector(0, 0), NewVector(4, 0), NewVector(4, 1), NewVector(1, 1), NewVector(1, 4), NewVector(0, 4)}
+	checkRefined(t, ell.Refine(nil, &RefineOptions{MinAngle: minAngle}), 7, minAngle, 0)
+
+	hole := NewRectangle(4, 4, 2, 2).ToPath()
+	holed := square.Refine([]*Path{hole}, opts)
+	checkRefined(t, holed, 96, minAngle, 2)
+	for _, tri := range *holed {
+		if hole.Contains(tri.Centroid()) {
+			t.Error("triangles should not lie inside the hole")
+		}
+	}
+}
+
+func TestRefineDegenerate(t *testing.T) {
+	if nil != (&Path{NewVector(0, 0), NewVector(1, 1), NewVector(1, 1)}).Refine(nil, nil) {
+		t.Error("path with two distinct points should not be refined")
+	}
+	collinear := &Path{NewVector(0, 0), NewVector(1, 0), NewVector(2, 0)}
+	if tris := collinear.Refine(nil, nil); nil == tris || 0 != len(*tris) {
+		t.Error("path with no area should give no triangles")
+	}
+}
+
+func TestRefineMaxPoints(t *testing.T) {
+	square := NewRectangle(0, 0, 10, 10).ToPath()
+	tris := square.Refine(nil, &RefineOptions{MaxArea: 0.01, MaxPoints: 50})
+	if nil == tris || len(*tris) > 2*(4+50) {
+		t.Error("refinement should stop after adding 50 points")
+	}
+}
+
+func TestRefineSharpCorner(t *testing.T) {
+	angle := 10 * math.Pi / 180
+	wedge := &Path{NewVector(0, 0), NewVector(10, 0), NewVector(10*math.Cos(angle), 10*math.Sin(angle))}
+	tris := wedge.Refine(nil, &RefineOptions{MinAngle: 25 * math.Pi / 180, MaxArea: 0.5})
+	checkRefined(t, tris, signedArea(*wedge), 0, 0.5)
+	if nil == tris || len(*tris) > 200 {
+		t.Fatal("refinement should finish at a sharp corner")
+	}
+	lower := NewLine(NewVector(0, 0), NewVector(10, 0))
+	upper := NewLine(NewVector(0, 0), (*wedge)[2])
+	for _, tri := range *tris {
+		if tri.MinAngle() >= 25*math.Pi/180 {
+			continue
+		}
+		onLower, onUpper := false, false
+		for _, p := range tri.Points {
+			onLower = onLower || lower.DistanceToPoint(p, true) < 1e-9
+			onUpper = onUpper || upper.DistanceToPoint(p, true) < 1e-9
+		}
+		if !onLower || !onUpper {
+			t.Error("only triangles across the sharp corner should have small angles")
+		}
+	}
+}
@@ -0,0 +1,493 @@
+package geo2
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// RTree is a spatial index of items keyed by their Rectangle bounds.
+// Items are inserted using the R* tree heuristics, or a static tree can
+// be built all at once using sort-tile-recursive packing with
+// BulkLoadRTree, which produces a tree with much less overlap.
+//
+// An RTree is not safe for concurrent use while it is being modified
+type RTree struct {
+	minEntries int
+	maxEntries int
+	root       *rtreeNode
+	size       int
+}
+
+// RTreeEntry pairs a value with its bounds
+// for bulk loading into an RTree
+type RTreeEntry struct {
+	Value  interface{}
+	Bounds *Rectangle
+}
+
+type rtreeNode struct {
+	// level is the height of this node above the leaves (which are 0)
+	level   int
+	entries []*rtreeEntry
+}
+
+type rtreeEntry struct {
+	bounds Rectangle
+	child  *rtreeNode
+	value  interface{}
+}
+
+// NewRTree creates an empty RTree whose nodes hold up to maxEntries
+// entries. A value below 4 uses the default of 16 entries
+func NewRTree(maxEntries int) *RTree {
+	if maxEntries < 4 {
+		maxEntries = 16
+	}
+	minEntries := int(math.Max(2, math.Floor(float64(maxEntries)*0.4)))
+	return &RTree{
+		minEntries: minEntries,
+		maxEntries: maxEntries,
+		root:       &rtreeNode{},
+	}
+}
+
+// BulkLoadRTree builds an RTree containing all of the given entries at
+// once using sort-tile-recursive (STR) packing. This is much faster
+// than inserting the items one by one and produces a better tree for
+// static data. Items can still be inserted and deleted afterwards
+func BulkLoadRTree(entries []RTreeEntry, maxEntries int) *RTree {
+	tree := NewRTree(maxEntries)
+	if 0 == len(entries) {
+		return tree
+	}
+	level := make([]*rtreeEntry, len(entries))
+	for i, e := range entries {
+		level[i] = &rtreeEntry{bounds: *e.Bounds.Clone().Normalize(), value: e.Value}
+	}
+	tree.size = len(entries)
+
+	height := 0
+	for {
+		nodes := strPack(level, tree.maxEntries, height)
+		if 1 == len(nodes) {
+			tree.root = nodes[0]
+			return tree
+		}
+		level = make([]*rtreeEntry, len(nodes))
+		for i, node := range nodes {
+			level[i] = &rtreeEntry{bounds: *node.bounds(), child: node}
+		}
+		height++
+	}
+}
+
+// strPack tiles the given entries into nodes of the given level
+func strPack(entries []*rtreeEntry, capacity, level int) []*rtreeNode {
+	leaves := (len(entries) + capacity - 1) / capacity
+	slices := int(math.Ceil(math.Sqrt(float64(leaves))))
+	sliceSize := slices * capacity
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].centerX() < entries[j].centerX()
+	})
+	var nodes []*rtreeNode
+	for start := 0; start < len(entries); start += sliceSize {
+		slice := entries[start:minInt(start+sliceSize, len(entries))]
+		sort.Slice(slice, func(i, j int) bool {
+			return slice[i].centerY() < slice[j].centerY()
+		})
+		for i := 0; i < len(slice); i += capacity {
+			run := slice[i:minInt(i+capacity, len(slice))]
+			node := &rtreeNode{level: level, entries: make([]*rtreeEntry, len(run))}
+			copy(node.entries, run)
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Len returns the number of items in this tree
+func (tree *RTree) Len() int {
+	return tree.size
+}
+
+// Insert adds the given item to this tree with the given bounds.
+// The same item may be inserted more than once
+func (tree *RTree) Insert(item interface{}, bounds *Rectangle) {
+	entry := &rtreeEntry{bounds: *bounds.Clone().Normalize(), value: item}
+	tree.insertEntries([]*rtreeEntry{entry}, []int{0})
+	tree.size++
+}
+
+// insertEntries inserts each of the given entries into a node at the
+// matching level, along with any entries evicted for reinsertion
+func (tree *RTree) insertEntries(entries []*rtreeEntry, levels []int) {
+	// each level may only be reinserted into once per insertion
+	reinserted := make(map[int]bool)
+	for len(entries) > 0 {
+		entry, level := entries[0], levels[0]
+		entries, levels = entries[1:], levels[1:]
+
+		var evicted []*rtreeEntry
+		split := tree.insert(tree.root, entry, level, reinserted, &evicted)
+		if nil != split {
+			old := tree.root
+			tree.root = &rtreeNode{
+				level: old.level + 1,
+				entries: []*rtreeEntry{
+					{bounds: *old.bounds(), child: old},
+					{bounds: *split.bounds(), child: split},
+				},
+			}
+		}
+		for _, e := range evicted {
+			entries = append(entries, e)
+			levels = append(levels, e.level())
+		}
+	}
+}
+
+// insert recursively places the entry into the subtree of the given
+// node, returning the new sibling of node if it had to be split
+func (tree *RTree) insert(node *rtreeNode, entry *rtreeEntry, level int,
+	reinserted map[int]bool, evicted *[]*rtreeEntry) *rtreeNode {
+	if node.level == level {
+		node.entries = append(node.entries, entry)
+	} else {
+		parent := node.chooseSubtree(&entry.bounds)
+		split := tree.insert(parent.child, entry, level, reinserted, evicted)
+		parent.bounds = *parent.child.bounds()
+		if nil != split {
+			node.entries = append(node.entries, &rtreeEntry{bounds: *split.bounds(), child: split})
+		}
+	}
+
+	if len(node.entries) <= tree.maxEntries {
+		return nil
+	}
+	if node != tree.root && !reinserted[node.level] {
+		reinserted[node.level] = true
+		*evicted = append(*evicted, node.evict(tree.maxEntries*3/10)...)
+		return nil
+	}
+	return node.split(tree.minEntries)
+}
+
+// chooseSubtree picks the entry of this node which should receive
+// the given bounds, using the R* tree criteria
+func (node *rtreeNode) chooseSubtree(bounds *Rectangle) *rtreeEntry {
+	var best *rtreeEntry
+	bestOverlap, bestEnlarge, bestArea := math.Inf(1), math.Inf(1), math.Inf(1)
+	leaves := 1 == node.level
+	for _, e := range node.entries {
+		grown := e.bounds.Union(bounds)
+		area := e.bounds.Area()
+		enlarge := grown.Area() - area
+		overlap := 0.0
+		if leaves {
+			// only minimize the overlap for nodes pointing at leaves
+			for _, other := range node.entries {
+				if other == e {
+					continue
+				}
+				overlap += overlapArea(grown, &other.bounds) - overlapArea(&e.bounds, &other.bounds)
+			}
+		}
+		if overlap < bestOverlap ||
+			(overlap == bestOverlap && enlarge < bestEnlarge) ||
+			(overlap == bestOverlap && enlarge == bestEnlarge && area < bestArea) {
+			best, bestOverlap, bestEnlarge, bestArea = e, overlap, enlarge, area
+		}
+	}
+	return best
+}
+
+// evict removes the count entries furthest from the center of this
+// node, returning them in order of increasing distance for reinsertion
+func (node *rtreeNode) evict(count int) []*rtreeEntry {
+	if count < 1 {
+		count = 1
+	}
+	center := node.bounds().Center()
+	sort.Slice(node.entries, func(i, j int) bool {
+		di := node.entries[i].bounds.Center().Sub(center).LengthSqd()
+		dj := node.entries[j].bounds.Center().Sub(center).LengthSqd()
+		return di < dj
+	})
+	keep := len(node.entries) - count
+	evicted := make([]*rtreeEntry, count)
+	copy(evicted, node.entries[keep:])
+	node.entries = node.entries[:keep]
+	return evicted
+}
+
+// split divides the entries of this node in two using the R* split
+// heuristics, keeping one group and returning a new sibling node with
+// the other
+func (node *rtreeNode) split(minEntries int) *rtreeNode {
+	entries := node.entries
+	count := len(entries)
+	sorters := []func(i, j int) bool{
+		func(i, j int) bool {
+			return entries[i].bounds.X < entries[j].bounds.X ||
+				(entries[i].bounds.X == entries[j].bounds.X && entries[i].bounds.Right() < entries[j].bounds.Right())
+		},
+		func(i, j int) bool {
+			return entries[i].bounds.Y < entries[j].bounds.Y ||
+				(entries[i].bounds.Y == entries[j].bounds.Y && entries[i].bounds.Bottom() < entries[j].bounds.Bottom())
+		},
+	}
+
+	// choose the axis with the smallest total margin
+	bestAxis := 0
+	bestMargin := math.Inf(1)
+	for axis, less := range sorters {
+		sort.Slice(entries, less)
+		margin := 0.0
+		for k := minEntries; k <= count-minEntries; k++ {
+			a, b := groupBounds(entries[:k]), groupBounds(entries[k:])
+			margin += a.Width + a.Height + b.Width + b.Height
+		}
+		if margin < bestMargin {
+			bestAxis, bestMargin = axis, margin
+		}
+	}
+
+	// then the distribution with the least overlap, then area
+	sort.Slice(entries, sorters[bestAxis])
+	bestK := minEntries
+	bestOverlap, bestArea := math.Inf(1), math.Inf(1)
+	for k := minEntries; k <= count-minEntries; k++ {
+		a, b := groupBounds(entries[:k]), groupBounds(entries[k:])
+		overlap := overlapArea(a, b)
+		area := a.Area() + b.Area()
+		if overlap < bestOverlap || (overlap == bestOverlap && area < bestArea) {
+			bestK, bestOverlap, bestArea = k, overlap, area
+		}
+	}
+
+	sibling := &rtreeNode{level: node.level}
+	sibling.entries = append(sibling.entries, entries[bestK:]...)
+	node.entries = append([]*rtreeEntry(nil), entries[:bestK]...)
+	return sibling
+}
+
+// Delete removes the given item with the given bounds from this tree,
+// returning false if it could not be found. The bounds must match (or
+// at least be contained by) the bounds the item was inserted with
+func (tree *RTree) Delete(item interface{}, bounds *Rectangle) bool {
+	area := bounds.Clone().Normalize()
+	path := tree.findLeaf(tree.root, item, area, nil)
+	if nil == path {
+		return false
+	}
+	tree.size--
+
+	// condense the tree, removing underfull nodes along the
+	// path and reinserting their entries afterwards
+	var orphans []*rtreeEntry
+	for i := len(path) - 1; i > 0; i-- {
+		node, parent := path[i], path[i-1]
+		for j, e := range parent.entries {
+			if e.child != node {
+				continue
+			}
+			if len(node.entries) < tree.minEntries {
+				orphans = append(orphans, node.entries...)
+				parent.entries = append(parent.entries[:j], parent.entries[j+1:]...)
+			} else {
+				e.bounds = *node.bounds()
+			}
+			break
+		}
+	}
+	for tree.root.level > 0 && 1 == len(tree.root.entries) {
+		tree.root = tree.root.entries[0].child
+	}
+	if tree.root.level > 0 && 0 == len(tree.root.entries) {
+		tree.root = &rtreeNode{}
+	}
+
+	// orphaned subtrees taller than the (possibly shortened)
+	// tree are broken down so that they can still be placed
+	var entries []*rtreeEntry
+	var levels []int
+	for len(orphans) > 0 {
+		e := orphans[len(orphans)-1]
+		orphans = orphans[:len(orphans)-1]
+		if e.level() > tree.root.level {
+			orphans = append(orphans, e.child.entries...)
+			continue
+		}
+		entries = append(entries, e)
+		levels = append(levels, e.level())
+	}
+	tree.insertEntries(entries, levels)
+	return true
+}
+
+// findLeaf returns the path from the given node down to the leaf
+// holding the given item, after removing the item from that leaf
+func (tree *RTree) findLeaf(node *rtreeNode, item interface{}, bounds *Rectangle, path []*rtreeNode) []*rtreeNode {
+	path = append(path, node)
+	for i, e := range node.entries {
+		if !e.bounds.ContainsRectangle(bounds) {
+			continue
+		}
+		if 0 == node.level {
+			if e.value == item {
+				node.entries = append(node.entries[:i], node.entries[i+1:]...)
+				return path
+			}
+			continue
+		}
+		if found := tree.findLeaf(e.child, item, bounds, path); nil != found {
+			return found
+		}
+	}
+	return nil
+}
+
+// Search returns every item whose bounds overlap
+// or touch the given rectangle
+func (tree *RTree) Search(rect *Rectangle) []interface{} {
+	area := rect.Clone().Normalize()
+	var result []interface{}
+	stack := []*rtreeNode{tree.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range node.entries {
+			if !e.bounds.Overlaps(area) {
+				continue
+			}
+			if 0 == node.level {
+				result = append(result, e.value)
+			} else {
+				stack = append(stack, e.child)
+			}
+		}
+	}
+	return result
+}
+
+// SearchPoint returns every item whose bounds contain the given point
+func (tree *RTree) SearchPoint(vec *Vector) []interface{} {
+	return tree.Search(NewRectangle(vec.X, vec.Y, 0, 0))
+}
+
+// RTreeDistanceFunc returns the distance from an item to a point
+type RTreeDistanceFunc func(item interface{}, point *Vector) float64
+
+// KNearest returns up to k items nearest to the given point, closest
+// first. The distance to each item is measured with the given function,
+// which must never return less than the distance from the point to
+// the bounds of the item, or nil to use the distance to the bounds
+func (tree *RTree) KNearest(vec *Vector, k int, distance RTreeDistanceFunc) []interface{} {
+	var result []interface{}
+	if k <= 0 {
+		return result
+	}
+	queue := &priorityQueue{}
+	for _, e := range tree.root.entries {
+		heap.Push(queue, priorityItem{boundsDistance(&e.bounds, vec), rtreeCandidate{e, false}})
+	}
+	for queue.Len() > 0 && len(result) < k {
+		c := heap.Pop(queue).(priorityItem).value.(rtreeCandidate)
+		if nil == c.entry.child {
+			// items are re-queued with their real distance, and
+			// are only known to be closest once popped again
+			if nil == distance || c.exact {
+				result = append(result, c.entry.value)
+				continue
+			}
+			heap.Push(queue, priorityItem{distance(c.entry.value, vec), rtreeCandidate{c.entry, true}})
+			continue
+		}
+		for _, e := range c.entry.child.entries {
+			heap.Push(queue, priorityItem{boundsDistance(&e.bounds, vec), rtreeCandidate{e, false}})
+		}
+	}
+	return result
+}
+
+type rtreeCandidate struct {
+	entry *rtreeEntry
+	exact bool
+}
+
+// priorityItem is a value held in a priorityQueue
+type priorityItem struct {
+	priority float64
+	value    interface{}
+}
+
+// priorityQueue is a min heap of items by their
+// priority, for use with the container/heap package
+type priorityQueue []priorityItem
+
+func (q priorityQueue) Len() int            { return len(q) }
+func (q priorityQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q priorityQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *priorityQueue) Push(x interface{}) { *q = append(*q, x.(priorityItem)) }
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// bounds returns the bounds of all entries in this node
+func (node *rtreeNode) bounds() *Rectangle {
+	return groupBounds(node.entries)
+}
+
+// level returns the level of the node this entry belongs in
+func (e *rtreeEntry) level() int {
+	if nil == e.child {
+		return 0
+	}
+	return e.child.level + 1
+}
+
+func (e *rtreeEntry) centerX() float64 {
+	return e.bounds.X + e.bounds.Width*0.5
+}
+
+func (e *rtreeEntry) centerY() float64 {
+	return e.bounds.Y + e.bounds.Height*0.5
+}
+
+// groupBounds returns the union of the bounds of the given entries
+func groupBounds(entries []*rtreeEntry) *Rectangle {
+	if 0 == len(entries) {
+		return NewRectangle(0, 0, 0, 0)
+	}
+	result := entries[0].bounds.Clone()
+	for _, e := range entries[1:] {
+		result = result.Union(&e.bounds)
+	}
+	return result
+}
+
+// overlapArea returns the area shared by the given rectangles
+func overlapArea(a, b *Rectangle) float64 {
+	if shared := a.Intersection(b); nil != shared {
+		return shared.Area()
+	}
+	return 0
+}
+
+// boundsDistance returns the distance from the given point to
+// the given rectangle, or zero if the point is inside it
+func boundsDistance(rect *Rectangle, vec *Vector) float64 {
+	return math.Max(0, rect.SignedDistanceTo(vec))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,127 @@
+package geo2
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomRectangles(seed int64, count int) []*Rectangle {
+	rng := rand.New(rand.NewSource(seed))
+	rects := make([]*Rectangle, count)
+	for i := range rects {
+		rects[i] = NewRectangle(rng.Float64()*1000, rng.Float64()*1000, rng.Float64()*15, rng.Float64()*15)
+	}
+	return rects
+}
+
+func checkRTreeSearch(t *testing.T, tree *RTree, rects []*Rectangle, removed map[int]bool) {
+	t.Helper()
+	for _, area := range []*Rectangle{
+		NewRectangle(100, 100, 200, 50),
+		NewRectangle(500, 0, 30, 1000),
+		NewRectangle(-10, -10, 2000, 2000),
+	} {
+		var expected []int
+		for i, r := range rects {
+			if !removed[i] && r.Overlaps(area) {
+				expected = append(expected, i)
+			}
+		}
+		if !equalInts(sortedInts(tree.Search(area)), expected) {
+			t.Error("search should match a linear scan")
+		}
+	}
+}
+
+func TestRTreeInsertSearch(t *testing.T) {
+	rects := randomRectangles(1, 1000)
+	tree := NewRTree(8)
+	for i, r := range rects {
+		tree.Insert(i, r)
+	}
+	if tree.Len() != len(rects) {
+		t.Error("tree should hold every inserted item")
+	}
+	checkRTreeSearch(t, tree, rects, nil)
+}
+
+func TestRTreeBulkLoad(t *testing.T) {
+	rects := randomRectangles(2, 1000)
+	entries := make([]RTreeEntry, len(rects))
+	for i, r := range rects {
+		entries[i] = RTreeEntry{i, r}
+	}
+	tree := BulkLoadRTree(entries, 16)
+	checkRTreeSearch(t, tree, rects, nil)
+
+	point := rects[10].Center()
+	found := false
+	for _, item := range tree.SearchPoint(point) {
+		found = found || item == 10
+	}
+	if !found {
+		t.Error("point search should find the item under the point")
+	}
+}
+
+func TestRTreeDelete(t *testing.T) {
+	rects := randomRectangles(3, 500)
+	tree := NewRTree(6)
+	for i, r := range rects {
+		tree.Insert(i, r)
+	}
+	removed := make(map[int]bool)
+	for i := 0; i < len(rects); i += 3 {
+		if !tree.Delete(i, rects[i]) {
+			t.Error("inserted items should be deleted")
+		}
+		removed[i] = true
+	}
+	if tree.Delete(0, rects[0]) {
+		t.Error("deleted items should not be deleted twice")
+	}
+	checkRTreeSearch(t, tree, rects, removed)
+	for i := range rects {
+		if !removed[i] {
+			tree.Delete(i, rects[i])
+		}
+	}
+	if tree.Len() != 0 || len(tree.Search(NewRectangle(0, 0, 2000, 2000))) != 0 {
+		t.Error("tree should be empty after deleting everything")
+	}
+}
+
+func TestRTreeKNearest(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	points := make([]*Vector, 300)
+	entries := make([]RTreeEntry, len(points))
+	for i := range points {
+		points[i] = NewVector(rng.Float64()*100, rng.Float64()*100)
+		entries[i] = RTreeEntry{i, NewRectangle(points[i].X, points[i].Y, 0, 0)}
+	}
+	tree := BulkLoadRTree(entries, 8)
+	query := NewVector(50, 50)
+	result := tree.KNearest(query, 5, func(item interface{}, p *Vector) float64 {
+		return points[item.(int)].Clone().Sub(p).Length()
+	})
+	if len(result) != 5 {
+		t.Fatal("k nearest should return k items")
+	}
+	last := 0.0
+	for _, item := range result {
+		d := points[item.(int)].Clone().Sub(query).Length()
+		if d < last {
+			t.Error("nearest items should be sorted by distance")
+		}
+		last = d
+	}
+	closer := 0
+	for _, p := range points {
+		if p.Clone().Sub(query).Length() < last {
+			closer++
+		}
+	}
+	if closer != 4 {
+		t.Error("k nearest should find the closest items")
+	}
+}
@@ -0,0 +1,194 @@
+package geo2
+
+import "math"
+
+// convexOverlap tests two convex polygons for overlap using the
+// separating axis theorem. Touching polygons are considered to
+// overlap. The winding of each polygon does not matter
+func convexOverlap(a, b []*Vector) bool {
+	for _, poly := range [][]*Vector{a, b} {
+		for i := range poly {
+			axis := edgeNormal(poly[i], poly[(i+1)%len(poly)])
+			if 0 == axis.LengthSqd() {
+				continue
+			}
+			minA, maxA := projectPoints(a, axis)
+			minB, maxB := projectPoints(b, axis)
+			if maxA < minB || maxB < minA {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// edgeNormal returns the (non-normalized) normal of the edge a -> b
+func edgeNormal(a, b *Vector) *Vector {
+	return NewVector(b.Y-a.Y, a.X-b.X)
+}
+
+// projectPoints returns the range covered by the
+// given points when projected onto the given axis
+func projectPoints(points []*Vector, axis *Vector) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		d := p.Dot(axis)
+		min = math.Min(min, d)
+		max = math.Max(max, d)
+	}
+	return min, max
+}
+
+// Manifold describes the contact between two overlapping shapes
+type Manifold struct {
+	// Normal is the unit collision normal, pointing from A towards B
+	Normal *Vector
+	// Depth is how far the shapes overlap along the normal
+	Depth float64
+	// MTV is the minimum translation vector, which separates
+	// the shapes when B is moved by it (or A by its negation)
+	MTV *Vector
+	// Contacts holds one or two points where the shapes touch
+	Contacts []*Vector
+}
+
+// CollideConvex tests two convex polygons for overlap using the
+// separating axis theorem, returning the contact manifold or nil if
+// they do not overlap. The polygons may be wound in either direction.
+// Shapes which only touch produce a manifold with a depth of zero
+func CollideConvex(a, b *Path) *Manifold {
+	if len(*a) < 3 || len(*b) < 3 {
+		return nil
+	}
+	polyA := counterClockwise(*a)
+	polyB := counterClockwise(*b)
+
+	edgeA, sepA := maxSeparation(polyA, polyB)
+	if sepA > 0 {
+		return nil
+	}
+	edgeB, sepB := maxSeparation(polyB, polyA)
+	if sepB > 0 {
+		return nil
+	}
+
+	// prefer A as the reference unless B separates noticeably more,
+	// so that the result is stable when the separations are close
+	ref, inc, edge, flip := polyA, polyB, edgeA, false
+	if sepB > sepA+1e-9*math.Max(1, math.Abs(sepA)) {
+		ref, inc, edge, flip = polyB, polyA, edgeB, true
+	}
+
+	v1 := ref[edge]
+	v2 := ref[(edge+1)%len(ref)]
+	normal := edgeNormal(v1, v2).Normalize()
+	tangent := v2.Clone().Sub(v1).Normalize()
+
+	// the incident edge is the one most opposed to the reference normal
+	incident := 0
+	minDot := math.Inf(1)
+	for i := range inc {
+		n := edgeNormal(inc[i], inc[(i+1)%len(inc)]).Normalize()
+		if d := n.Dot(normal); d < minDot {
+			incident, minDot = i, d
+		}
+	}
+	points := []*Vector{inc[incident].Clone(), inc[(incident+1)%len(inc)].Clone()}
+
+	// clip the incident edge to the sides of the reference edge
+	points = clipSegment(points, tangent.Clone().Negate(), -tangent.Dot(v1))
+	points = clipSegment(points, tangent, tangent.Dot(v2))
+
+	manifold := &Manifold{Depth: -math.Max(sepA, sepB)}
+	offset := normal.Dot(v1)
+	for _, p := range points {
+		if normal.Dot(p)-offset <= 1e-9*math.Max(1, math.Abs(offset)) {
+			manifold.Contacts = append(manifold.Contacts, p)
+		}
+	}
+	if flip {
+		normal.Negate()
+	}
+	manifold.Normal = normal
+	manifold.MTV = normal.Clone().MultiplyScalar(manifold.Depth)
+	return manifold
+}
+
+// Collide tests this convex path against another, returning the
+// contact manifold or nil if they do not overlap (see CollideConvex)
+func (path *Path) Collide(other *Path) *Manifold {
+	return CollideConvex(path, other)
+}
+
+// maxSeparation finds the edge of a whose outward normal separates
+// the polygons the most, along with that (signed) separation.
+// Both polygons must be wound counter clockwise
+func maxSeparation(a, b []*Vector) (int, float64) {
+	best, bestSep := 0, math.Inf(-1)
+	for i := range a {
+		normal := edgeNormal(a[i], a[(i+1)%len(a)])
+		if 0 == normal.LengthSqd() {
+			continue
+		}
+		normal.Normalize()
+		sep := math.Inf(1)
+		for _, p := range b {
+			sep = math.Min(sep, normal.Dot(p.Clone().Sub(a[i])))
+		}
+		if sep > bestSep {
+			best, bestSep = i, sep
+		}
+	}
+	return best, bestSep
+}
+
+// clipSegment keeps the part of the given segment for which
+// normal . p <= offset
+func clipSegment(points []*Vector, normal *Vector, offset float64) []*Vector {
+	if len(points) < 2 {
+		return points
+	}
+	d0 := normal.Dot(points[0]) - offset
+	d1 := normal.Dot(points[1]) - offset
+	var result []*Vector
+	if d0 <= 0 {
+		result = append(result, points[0])
+	}
+	if d1 <= 0 {
+		result = append(result, points[1])
+	}
+	if d0*d1 < 0 {
+		t := d0 / (d0 - d1)
+		result = append(result, NewLine(points[0], points[1]).GetPosition(t))
+	}
+	return result
+}
+
+// counterClockwise returns the given points wound counter
+// clockwise (positive signed area), reversing a copy if needed
+func counterClockwise(points []*Vector) []*Vector {
+	if signedArea(points) >= 0 {
+		return points
+	}
+	reversed := make([]*Vector, len(points))
+	for i, p := range points {
+		reversed[len(points)-1-i] = p
+	}
+	return reversed
+}
+
+// signedArea returns the signed area of the given closed polygon,
+// which is positive when wound counter clockwise (with y up)
+func signedArea(points []*Vector) float64 {
+	area := 0.0
+	for i := 2; i < len(points); i++ {
+		area += orient(points[0], points[i-1], points[i])
+	}
+	return area * 0.5
+}
+
+// orient returns twice the signed area of the triangle abc,
+// which is positive when it is wound counter clockwise
+func orient(a, b, c *Vector) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
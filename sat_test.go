@@ -0,0 +1,53 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCollideConvexBoxes(t *testing.T) {
+	a := NewRectangle(0, 0, 2, 2).ToPath()
+	b := NewRectangle(1.5, 0.5, 2, 1).ToPath()
+	m := CollideConvex(a, b)
+	if nil == m {
+		t.Fatal("overlapping boxes should collide")
+	}
+	if !m.Normal.CloseEnough(NewVector(1, 0), 0.0001) || math.Abs(m.Depth-0.5) > 1e-9 {
+		t.Error("normal should point from a to b along the shallowest axis")
+	}
+	if !m.MTV.CloseEnough(NewVector(0.5, 0), 0.0001) {
+		t.Error("mtv should be the normal scaled by the depth")
+	}
+	if len(m.Contacts) != 2 {
+		t.Fatal("face to face contact should produce two points")
+	}
+	for _, c := range m.Contacts {
+		if !(math.Abs(c.X-1.5) < 1e-9 || math.Abs(c.X-2) < 1e-9) || c.Y < 0.5 || c.Y > 1.5 {
+			t.Error("contact points should lie on the overlapping faces")
+		}
+	}
+
+	moved := b.Transformed(TranslationMatrix(m.MTV.X, m.MTV.Y)).(*Path)
+	if m := CollideConvex(a, moved); nil != m && m.Depth > 1e-9 {
+		t.Error("moving b by the mtv should separate the boxes")
+	}
+	if nil != CollideConvex(a, NewRectangle(3, 0, 1, 1).ToPath()) {
+		t.Error("separated boxes should not collide")
+	}
+}
+
+func TestCollideConvexTriangleCorner(t *testing.T) {
+	// a triangle pointing down into the top of a box, wound clockwise
+	tri := NewTriangle([]*Vector{NewVector(0, 0.8), NewVector(-1, 3), NewVector(1, 3)})
+	box := NewRectangle(-2, -1, 4, 2).ToPath()
+	m := tri.ToPath().Collide(box)
+	if nil == m {
+		t.Fatal("triangle tip inside the box should collide")
+	}
+	if !m.Normal.CloseEnough(NewVector(0, -1), 0.0001) || math.Abs(m.Depth-0.2) > 1e-9 {
+		t.Error("normal should push the box down out of the triangle")
+	}
+	if len(m.Contacts) != 1 || !m.Contacts[0].CloseEnough(NewVector(0, 0.8), 0.0001) {
+		t.Error("a vertex contact should produce the single tip point")
+	}
+}
@@ -0,0 +1,382 @@
+package geo2
+
+import "math"
+
+// Skeleton is a graph running through the middle of a polygon
+type Skeleton struct {
+	// Nodes are the positions of the skeleton nodes
+	Nodes []*Vector
+	// Heights is the distance from each node to the boundary
+	// of the polygon, which is the height of the roof over
+	// that node for a straight skeleton
+	Heights []float64
+	// Edges are pairs of indices of connected nodes
+	Edges [][2]int
+
+	tolerance float64
+	arcs      map[[2]int]bool
+}
+
+func newSkeleton(tolerance float64) *Skeleton {
+	return &Skeleton{tolerance: tolerance, arcs: make(map[[2]int]bool)}
+}
+
+// addNode adds a node, reusing any existing node at the same place
+func (s *Skeleton) addNode(point *Vector, height float64) int {
+	for i, node := range s.Nodes {
+		if node.Clone().Sub(point).Length() <= s.tolerance && math.Abs(s.Heights[i]-height) <= s.tolerance {
+			return i
+		}
+	}
+	s.Nodes = append(s.Nodes, point.Clone())
+	s.Heights = append(s.Heights, height)
+	return len(s.Nodes) - 1
+}
+
+// addEdge connects two nodes, ignoring repeated and empty edges
+func (s *Skeleton) addEdge(a, b int) {
+	if a == b {
+		return
+	}
+	if a > b {
+		a, b = b, a
+	}
+	if s.arcs[[2]int{a, b}] {
+		return
+	}
+	s.arcs[[2]int{a, b}] = true
+	s.Edges = append(s.Edges, [2]int{a, b})
+}
+
+// Lines returns the edges of this skeleton as lines
+func (s *Skeleton) Lines() []*Line {
+	lines := make([]*Line, len(s.Edges))
+	for i, edge := range s.Edges {
+		lines[i] = NewLine(s.Nodes[edge[0]].Clone(), s.Nodes[edge[1]].Clone())
+	}
+	return lines
+}
+
+// skeletonEdge is an edge of the original polygon
+type skeletonEdge struct {
+	start, dir, normal *Vector
+}
+
+// skeletonVertex is a vertex of the shrinking wavefront which was
+// at point at the given time, lying between its left (incoming)
+// and right (outgoing) polygon edges
+type skeletonVertex struct {
+	point       *Vector
+	time        float64
+	velocity    *Vector
+	left, right int
+	node        int
+	prev, next  *skeletonVertex
+}
+
+// at returns the position of this vertex at the given time
+func (v *skeletonVertex) at(time float64) *Vector {
+	return v.velocity.Clone().MultiplyScalar(time - v.time).Add(v.point)
+}
+
+// skeletonLoops prepares the outer path and holes as counter clockwise
+// and clockwise loops so that the interior is always to their left
+func skeletonLoops(path *Path, holes []*Path) [][]*Vector {
+	outer := dedupeLoop(*path.Clone())
+	if len(outer) < 3 {
+		return nil
+	}
+	loops := [][]*Vector{counterClockwise(outer)}
+	for _, hole := range holes {
+		loop := dedupeLoop(*hole.Clone())
+		if len(loop) < 3 {
+			continue
+		}
+		loop = counterClockwise(loop)
+		for i, j := 0, len(loop)-1; i < j; i, j = i+1, j-1 {
+			loop[i], loop[j] = loop[j], loop[i]
+		}
+		loops = append(loops, loop)
+	}
+	return loops
+}
+
+// StraightSkeleton computes the straight skeleton of the area within
+// this path (treated as a closed loop) and outside of the given holes,
+// formed by the paths of the corners as every edge moves inwards at the
+// same speed. Each node records the time at which it was reached, which
+// is the height of a roof with a 45° pitch over the polygon. Repeated
+// points are ignored, and nil is returned if fewer than three remain
+func (path *Path) StraightSkeleton(holes []*Path) *Skeleton {
+	loops := skeletonLoops(path, holes)
+	if nil == loops {
+		return nil
+	}
+	bounds := NewRectangleFromPoints(loops[0])
+	tolerance := 1e-9 * (1 + math.Max(bounds.Width, bounds.Height))
+	skeleton := newSkeleton(tolerance)
+
+	var edges []skeletonEdge
+	active := make(map[*skeletonVertex]bool)
+	for _, loop := range loops {
+		first := len(edges)
+		for i, p := range loop {
+			dir := loop[(i+1)%len(loop)].Clone().Sub(p).Normalize()
+			edges = append(edges, skeletonEdge{p, dir, NewVector(-dir.Y, dir.X)})
+		}
+		var head, prev *skeletonVertex
+		for i, p := range loop {
+			left := first + (i+len(loop)-1)%len(loop)
+			v := newSkeletonVertex(edges, p, 0, left, first+i, skeleton.addNode(p, 0))
+			if nil == head {
+				head = v
+			} else {
+				prev.next, v.prev = v, prev
+			}
+			prev = v
+			active[v] = true
+		}
+		prev.next, head.prev = head, prev
+	}
+
+	now := 0.0
+	for step := 0; len(active) > 0 && step < 10*len(edges)*len(edges)+100; step++ {
+		event := nextSkeletonEvent(edges, active, now, tolerance)
+		if nil == event {
+			break
+		}
+		now = math.Max(now, event.time)
+		node := skeleton.addNode(event.point, event.time)
+
+		if nil == event.edge {
+			// two neighbouring vertices meet, removing the edge between them
+			a, b := event.vertex, event.vertex.next
+			skeleton.addEdge(a.node, node)
+			skeleton.addEdge(b.node, node)
+			c := newSkeletonVertex(edges, event.point, event.time, a.left, b.right, node)
+			c.prev, c.next = a.prev, b.next
+			a.prev.next, b.next.prev = c, c
+			delete(active, a)
+			delete(active, b)
+			active[c] = true
+		} else {
+			// a reflex vertex hits an opposite edge, splitting the
+			// wavefront in two (or joining two of them together)
+			v, s, e := event.vertex, event.edge, event.edge.next
+			skeleton.addEdge(v.node, node)
+			v1 := newSkeletonVertex(edges, event.point, event.time, v.left, s.right, node)
+			v2 := newSkeletonVertex(edges, event.point, event.time, s.right, v.right, node)
+			v1.prev, v1.next = v.prev, e
+			v2.prev, v2.next = s, v.next
+			v.prev.next, e.prev = v1, v1
+			s.next, v.next.prev = v2, v2
+			delete(active, v)
+			active[v1] = true
+			active[v2] = true
+		}
+
+		// wavefronts which have shrunk to a point or line are finished
+		for v := range active {
+			if v.next.next != v {
+				continue
+			}
+			loop := []*skeletonVertex{v, v.next}
+			if v.next == v {
+				loop = loop[:1]
+			}
+			if 2 == len(loop) {
+				skeleton.addEdge(loop[0].node, loop[1].node)
+			}
+			for _, dead := range loop {
+				delete(active, dead)
+			}
+		}
+	}
+	skeleton.arcs = nil
+	return skeleton
+}
+
+// newSkeletonVertex creates a wavefront vertex moving so that it
+// stays on both of its edges as they move inwards at unit speed
+func newSkeletonVertex(edges []skeletonEdge, point *Vector, time float64, left, right, node int) *skeletonVertex {
+	n1, n2 := edges[left].normal, edges[right].normal
+	velocity := NewVector(0, 0)
+	// edges facing each other have met, so the vertex is left behind
+	if denom := 1 + n1.Dot(n2); denom > 1e-9 {
+		velocity = n1.Clone().Add(n2).MultiplyScalar(1 / denom)
+	}
+	return &skeletonVertex{point: point.Clone(), time: time, velocity: velocity, left: left, right: right, node: node}
+}
+
+// skeletonEvent is the next change to the shape of the wavefront,
+// either the vertex meeting its next vertex, or the vertex
+// hitting the edge running from the given edge vertex
+type skeletonEvent struct {
+	time   float64
+	point  *Vector
+	vertex *skeletonVertex
+	edge   *skeletonVertex
+}
+
+// nextSkeletonEvent finds the earliest event for the active vertices
+func nextSkeletonEvent(edges []skeletonEdge, active map[*skeletonVertex]bool, now, tolerance float64) *skeletonEvent {
+	var best *skeletonEvent
+	consider := func(event *skeletonEvent) {
+		if nil == best || event.time < best.time-tolerance ||
+			// vertices meeting take priority over simultaneous splits
+			(event.time < best.time+tolerance && nil == event.edge && nil != best.edge) {
+			best = event
+		}
+	}
+
+	for a := range active {
+		b := a.next
+		dir := edges[a.right].dir
+		rate := dir.Dot(a.velocity) - dir.Dot(b.velocity)
+		if rate > 1e-12 {
+			gap := dir.Dot(b.at(now)) - dir.Dot(a.at(now))
+			time := now + gap/rate
+			if time >= now-tolerance {
+				time = math.Max(time, now)
+				point := a.at(time).Add(b.at(time)).MultiplyScalar(0.5)
+				consider(&skeletonEvent{time, point, a, nil})
+			}
+		}
+
+		if edges[a.left].dir.Cross(edges[a.right].dir) >= 0 {
+			continue
+		}
+		position := a.at(now)
+		for s := range active {
+			e := s.next
+			if s == a || e == a || s.right == a.left || s.right == a.right {
+				continue
+			}
+			edge := edges[s.right]
+			ahead := edge.normal.Dot(position.Clone().Sub(edge.start)) - now
+			approach := 1 - edge.normal.Dot(a.velocity)
+			if ahead < -tolerance || approach <= 1e-12 {
+				continue
+			}
+			time := now + math.Max(ahead, 0)/approach
+			point := a.at(time)
+			start, end := s.at(time), e.at(time)
+			length := edge.dir.Dot(end.Clone().Sub(start))
+			along := edge.dir.Dot(point.Clone().Sub(start))
+			if length < -tolerance || along < -tolerance || along > length+tolerance {
+				continue
+			}
+			consider(&skeletonEvent{time, point, a, s})
+		}
+	}
+	return best
+}
+
+// MedialAxis approximates the medial axis of the area within this path
+// (treated as a closed loop) and outside of the given holes, which is
+// the set of points with more than one closest point on the boundary.
+// The boundary is sampled every spacing units (or a hundredth of the
+// size of the path if zero) and the axis is built from the edges of
+// the Voronoi diagram of the samples which lie inside the polygon, with
+// the edges separating neighbouring samples removed. Each node records
+// its distance to the boundary. Like StraightSkeleton, returns nil
+// unless the path has at least three distinct points
+func (path *Path) MedialAxis(holes []*Path, spacing float64) *Skeleton {
+	loops := skeletonLoops(path, holes)
+	if nil == loops {
+		return nil
+	}
+	bounds := NewRectangleFromPoints(loops[0])
+	if spacing <= 0 {
+		spacing = math.Max(bounds.Width, bounds.Height) / 100
+	}
+
+	// sample every loop, remembering where each sample came from
+	var samples []*Vector
+	type sampleSource struct{ loop, index, count int }
+	var sources []sampleSource
+	for l, loop := range loops {
+		first := len(samples)
+		for i, p := range loop {
+			next := loop[(i+1)%len(loop)]
+			steps := int(math.Ceil(next.Clone().Sub(p).Length() / spacing))
+			for s := 0; s < steps; s++ {
+				samples = append(samples, NewLine(p, next).GetPosition(float64(s)/float64(steps)))
+			}
+		}
+		for i := first; i < len(samples); i++ {
+			sources = append(sources, sampleSource{l, i - first, len(samples) - first})
+		}
+	}
+	neighbours := func(a, b int) bool {
+		sa, sb := sources[a], sources[b]
+		if sa.loop != sb.loop {
+			return false
+		}
+		diff := absInt(sa.index - sb.index)
+		return 1 == diff || sa.count-1 == diff
+	}
+	inside := func(point *Vector) bool {
+		outer := Path(loops[0])
+		if !outer.Contains(point) {
+			return false
+		}
+		for _, hole := range loops[1:] {
+			if loop := Path(hole); loop.Contains(point) {
+				return false
+			}
+		}
+		return true
+	}
+
+	triangles := delaunay(samples)
+	skeleton := newSkeleton(1e-9 * (1 + math.Max(bounds.Width, bounds.Height)))
+	nodes := make([]int, len(triangles))
+	for i, tri := range triangles {
+		nodes[i] = -1
+		center := circumcenter(samples[tri[0]], samples[tri[1]], samples[tri[2]])
+		if nil != center && inside(center) {
+			nodes[i] = skeleton.addNode(center, center.Clone().Sub(samples[tri[0]]).Length())
+		}
+	}
+
+	// the Voronoi edge between two samples joins the circumcenters
+	// of the Delaunay triangles on either side of their shared edge
+	shared := make(map[[2]int]int)
+	for i, tri := range triangles {
+		for j := 0; j < 3; j++ {
+			a, b := tri[j], tri[(j+1)%3]
+			if other, ok := shared[[2]int{b, a}]; ok {
+				if -1 != nodes[i] && -1 != nodes[other] && !neighbours(a, b) {
+					skeleton.addEdge(nodes[i], nodes[other])
+				}
+				continue
+			}
+			shared[[2]int{a, b}] = i
+		}
+	}
+	skeleton.prune()
+	skeleton.arcs = nil
+	return skeleton
+}
+
+// prune removes nodes which are not part of any edge
+func (s *Skeleton) prune() {
+	remap := make([]int, len(s.Nodes))
+	for i := range remap {
+		remap[i] = -1
+	}
+	var nodes []*Vector
+	var heights []float64
+	for i, edge := range s.Edges {
+		for j, n := range edge {
+			if -1 == remap[n] {
+				remap[n] = len(nodes)
+				nodes = append(nodes, s.Nodes[n])
+				heights = append(heights, s.Heights[n])
+			}
+			s.Edges[i][j] = remap[n]
+		}
+	}
+	s.Nodes, s.Heights = nodes, heights
+}
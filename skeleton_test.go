@@ -0,0 +1,66 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStraightSkeletonRectangle(t *testing.T) {
+	skeleton := NewRectangle(0, 0, 4, 2).ToPath().StraightSkeleton(nil)
+	if 6 != len(skeleton.Nodes) || 5 != len(skeleton.Edges) {
+		t.Fatal("expected 6 nodes and 5 edges")
+	}
+	ridge := 0
+	for i, node := range skeleton.Nodes {
+		if node.Compare(NewVector(1, 1)) || node.Compare(NewVector(3, 1)) {
+			ridge++
+			if math.Abs(skeleton.Heights[i]-1) > 1e-9 {
+				t.Error("ridge nodes should have a height of 1")
+			}
+		}
+	}
+	if 2 != ridge {
+		t.Error("expected the ridge to run from (1, 1) to (3, 1)")
+	}
+}
+
+func TestStraightSkeletonSplit(t *testing.T) {
+	// the notch in the top creates a reflex corner
+	// which splits the shape in two as it shrinks
+	notched := &Path{
+		NewVector(0, 0), NewVector(10, 0), NewVector(10, 4), NewVector(6, 4),
+		NewVector(5, 1), NewVector(4, 4), NewVector(0, 4),
+	}
+	skeleton := notched.StraightSkeleton(nil)
+	if len(skeleton.Edges) != len(skeleton.Nodes)-1 {
+		t.Error("skeleton of a simple polygon should be a tree")
+	}
+	for i, node := range skeleton.Nodes {
+		if skeleton.Heights[i] > 0 && !notched.Contains(node) {
+			t.Error("skeleton nodes should lie inside the polygon")
+		}
+	}
+
+	holed := NewRectangle(0, 0, 10, 10).ToPath().StraightSkeleton([]*Path{NewRectangle(4, 4, 2, 2).ToPath()})
+	if 12 != len(holed.Nodes) || 12 != len(holed.Edges) {
+		t.Error("expected the skeleton around the hole to form a loop")
+	}
+}
+
+func TestMedialAxis(t *testing.T) {
+	axis := NewRectangle(0, 0, 10, 2).ToPath().MedialAxis(nil, 0.5)
+	if nil == axis || 0 == len(axis.Edges) {
+		t.Fatal("expected a medial axis")
+	}
+	if len(axis.Edges) != len(axis.Nodes)-1 {
+		t.Error("medial axis of a rectangle should be a tree")
+	}
+	for i, node := range axis.Nodes {
+		if node.X > 1.5 && node.X < 8.5 && math.Abs(node.Y-1) > 1e-9 {
+			t.Error("medial axis nodes should lie on the center line")
+		}
+		if math.Abs(axis.Heights[i]-math.Min(math.Min(node.Y, 2-node.Y), math.Min(node.X, 10-node.X))) > 0.25 {
+			t.Error("medial axis heights should match the distance to the boundary")
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package geo2
+
+import "math"
+
+// SpatialHash buckets items into a uniform grid of square cells by
+// their Rectangle bounds. It is well suited as a broad phase for many
+// moving objects of similar size, where cells are about the size of
+// the typical object. Updating an item which stays within the same
+// cells only replaces its bounds.
+//
+// Items are used as map keys and so must be comparable (eg: pointers).
+// A SpatialHash is not safe for concurrent use
+type SpatialHash struct {
+	cellSize float64
+	cells    map[hashCell][]interface{}
+	items    map[interface{}]*hashItem
+}
+
+type hashCell struct {
+	x, y int
+}
+
+type hashItem struct {
+	bounds   Rectangle
+	min, max hashCell
+}
+
+// NewSpatialHash creates an empty spatial hash with the given
+// cell size. Sizes of zero or less use a cell size of 1
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	if cellSize <= 0 {
+		cellSize = 1
+	}
+	return &SpatialHash{
+		cellSize: cellSize,
+		cells:    make(map[hashCell][]interface{}),
+		items:    make(map[interface{}]*hashItem),
+	}
+}
+
+// Len returns the number of items in this spatial hash
+func (hash *SpatialHash) Len() int {
+	return len(hash.items)
+}
+
+// Clear removes every item from this spatial hash
+func (hash *SpatialHash) Clear() {
+	hash.cells = make(map[hashCell][]interface{})
+	hash.items = make(map[interface{}]*hashItem)
+}
+
+// Insert adds the given item with the given bounds. If the
+// item is already present its bounds are updated instead
+func (hash *SpatialHash) Insert(item interface{}, bounds *Rectangle) {
+	if hash.Update(item, bounds) {
+		return
+	}
+	hi := &hashItem{bounds: *bounds.Clone().Normalize()}
+	hi.min, hi.max = hash.cellRange(&hi.bounds)
+	hash.items[item] = hi
+	hash.link(item, hi)
+}
+
+// Update moves the given item to its new bounds,
+// returning false if it is not in this spatial hash
+func (hash *SpatialHash) Update(item interface{}, bounds *Rectangle) bool {
+	hi, ok := hash.items[item]
+	if !ok {
+		return false
+	}
+	hi.bounds = *bounds.Clone().Normalize()
+	min, max := hash.cellRange(&hi.bounds)
+	if min == hi.min && max == hi.max {
+		return true
+	}
+	hash.unlink(item, hi)
+	hi.min, hi.max = min, max
+	hash.link(item, hi)
+	return true
+}
+
+// Remove removes the given item, returning false
+// if it is not in this spatial hash
+func (hash *SpatialHash) Remove(item interface{}) bool {
+	hi, ok := hash.items[item]
+	if !ok {
+		return false
+	}
+	hash.unlink(item, hi)
+	delete(hash.items, item)
+	return true
+}
+
+// QueryRectangle returns every item whose bounds
+// overlap or touch the given rectangle
+func (hash *SpatialHash) QueryRectangle(rect *Rectangle) []interface{} {
+	area := rect.Clone().Normalize()
+	return hash.query(area, func(bounds *Rectangle) bool {
+		return bounds.Overlaps(area)
+	})
+}
+
+// QueryCircle returns every item whose bounds overlap
+// or touch the circle with the given center and radius
+func (hash *SpatialHash) QueryCircle(center *Vector, radius float64) []interface{} {
+	area := NewRectangle(center.X-radius, center.Y-radius, radius*2, radius*2)
+	return hash.query(area, func(bounds *Rectangle) bool {
+		return bounds.SignedDistanceTo(center) <= radius
+	})
+}
+
+// QuerySegment returns every item whose bounds are crossed by the
+// given line segment, in the order the segment reaches their cells
+// (walking only the cells along the segment)
+func (hash *SpatialHash) QuerySegment(line *Line) []interface{} {
+	var result []interface{}
+	seen := make(map[interface{}]bool)
+	hash.walkSegment(line, func(cell hashCell) {
+		for _, item := range hash.cells[cell] {
+			if seen[item] {
+				continue
+			}
+			seen[item] = true
+			if segmentIntersectsRectangle(line.A, line.B, &hash.items[item].bounds) {
+				result = append(result, item)
+			}
+		}
+	})
+	return result
+}
+
+// Pairs returns every pair of items whose bounds overlap or touch.
+// Each pair is returned once, in no particular order
+func (hash *SpatialHash) Pairs() [][2]interface{} {
+	var result [][2]interface{}
+	for cell, items := range hash.cells {
+		for i, a := range items {
+			for _, b := range items[i+1:] {
+				ha, hb := hash.items[a], hash.items[b]
+				// overlapping items may share many cells, so only
+				// report each pair from the first cell they share
+				if !hash.firstSharedCell(ha, hb, cell) || !ha.bounds.Overlaps(&hb.bounds) {
+					continue
+				}
+				result = append(result, [2]interface{}{a, b})
+			}
+		}
+	}
+	return result
+}
+
+// firstSharedCell returns true if the given cell is the top left
+// most cell covered by both of the given items
+func (hash *SpatialHash) firstSharedCell(a, b *hashItem, cell hashCell) bool {
+	return (cell.x == maxInt(a.min.x, b.min.x) &&
+		cell.y == maxInt(a.min.y, b.min.y))
+}
+
+// query returns the items in the cells covered by the given
+// area which pass the given test, without duplicates
+func (hash *SpatialHash) query(area *Rectangle, test func(*Rectangle) bool) []interface{} {
+	var result []interface{}
+	seen := make(map[interface{}]bool)
+	min, max := hash.cellRange(area)
+	for x := min.x; x <= max.x; x++ {
+		for y := min.y; y <= max.y; y++ {
+			for _, item := range hash.cells[hashCell{x, y}] {
+				if seen[item] {
+					continue
+				}
+				seen[item] = true
+				if test(&hash.items[item].bounds) {
+					result = append(result, item)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// walkSegment calls visit for each cell crossed by the given
+// line segment, in order from A to B
+func (hash *SpatialHash) walkSegment(line *Line, visit func(hashCell)) {
+	cell := hash.cellFor(line.A.X, line.A.Y)
+	end := hash.cellFor(line.B.X, line.B.Y)
+	dir := line.ToVector()
+
+	stepX, stepY := 1, 1
+	if dir.X < 0 {
+		stepX = -1
+	}
+	if dir.Y < 0 {
+		stepY = -1
+	}
+	// distance along the segment (0-1) to the next cell boundary
+	// on each axis, and the distance needed to cross a whole cell
+	nextX, deltaX := math.Inf(1), math.Inf(1)
+	if 0 != dir.X {
+		boundary := float64(cell.x) * hash.cellSize
+		if stepX > 0 {
+			boundary += hash.cellSize
+		}
+		nextX = (boundary - line.A.X) / dir.X
+		deltaX = hash.cellSize / math.Abs(dir.X)
+	}
+	nextY, deltaY := math.Inf(1), math.Inf(1)
+	if 0 != dir.Y {
+		boundary := float64(cell.y) * hash.cellSize
+		if stepY > 0 {
+			boundary += hash.cellSize
+		}
+		nextY = (boundary - line.A.Y) / dir.Y
+		deltaY = hash.cellSize / math.Abs(dir.Y)
+	}
+
+	steps := absInt(end.x-cell.x) + absInt(end.y-cell.y)
+	visit(cell)
+	for i := 0; i < steps; i++ {
+		if nextX < nextY {
+			cell.x += stepX
+			nextX += deltaX
+		} else {
+			cell.y += stepY
+			nextY += deltaY
+		}
+		visit(cell)
+	}
+}
+
+// link adds the given item to each of its cells
+func (hash *SpatialHash) link(item interface{}, hi *hashItem) {
+	for x := hi.min.x; x <= hi.max.x; x++ {
+		for y := hi.min.y; y <= hi.max.y; y++ {
+			cell := hashCell{x, y}
+			hash.cells[cell] = append(hash.cells[cell], item)
+		}
+	}
+}
+
+// unlink removes the given item from each of its cells
+func (hash *SpatialHash) unlink(item interface{}, hi *hashItem) {
+	for x := hi.min.x; x <= hi.max.x; x++ {
+		for y := hi.min.y; y <= hi.max.y; y++ {
+			cell := hashCell{x, y}
+			items := hash.cells[cell]
+			for i, other := range items {
+				if other == item {
+					last := len(items) - 1
+					items[i] = items[last]
+					items[last] = nil
+					items = items[:last]
+					break
+				}
+			}
+			if 0 == len(items) {
+				delete(hash.cells, cell)
+			} else {
+				hash.cells[cell] = items
+			}
+		}
+	}
+}
+
+// cellRange returns the first and last cells covered by the given bounds
+func (hash *SpatialHash) cellRange(bounds *Rectangle) (hashCell, hashCell) {
+	return hash.cellFor(bounds.X, bounds.Y), hash.cellFor(bounds.Right(), bounds.Bottom())
+}
+
+// cellFor returns the cell containing the given position
+func (hash *SpatialHash) cellFor(x, y float64) hashCell {
+	return hashCell{
+		int(math.Floor(x / hash.cellSize)),
+		int(math.Floor(y / hash.cellSize)),
+	}
+}
+
+// segmentIntersectsRectangle returns true if the line segment a -> b
+// overlaps or touches the given rectangle (using liang-barsky clipping)
+func segmentIntersectsRectangle(a, b *Vector, rect *Rectangle) bool {
+	d := b.Clone().Sub(a)
+	t0, t1 := 0.0, 1.0
+	clip := func(p, q float64) bool {
+		if 0 == p {
+			return q >= 0
+		}
+		t := q / p
+		if p < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+		return t0 <= t1
+	}
+	return (clip(-d.X, a.X-rect.X) &&
+		clip(d.X, rect.Right()-a.X) &&
+		clip(-d.Y, a.Y-rect.Y) &&
+		clip(d.Y, rect.Bottom()-a.Y))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,96 @@
+package geo2
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSpatialHashQueries(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	hash := NewSpatialHash(25)
+	bounds := make([]*Rectangle, 300)
+	for i := range bounds {
+		bounds[i] = NewRectangle(rng.Float64()*500-250, rng.Float64()*500-250, rng.Float64()*30, rng.Float64()*30)
+		hash.Insert(i, bounds[i])
+	}
+
+	area := NewRectangle(-60, 20, 100, 80)
+	var expected []int
+	for i, b := range bounds {
+		if b.Overlaps(area) {
+			expected = append(expected, i)
+		}
+	}
+	if !equalInts(sortedInts(hash.QueryRectangle(area)), expected) {
+		t.Error("rectangle query should match a linear scan")
+	}
+
+	center := NewVector(10, -30)
+	expected = nil
+	for i, b := range bounds {
+		if b.DistanceTo(center) <= 40 || b.Contains(center) {
+			expected = append(expected, i)
+		}
+	}
+	if !equalInts(sortedInts(hash.QueryCircle(center, 40)), expected) {
+		t.Error("circle query should match a linear scan")
+	}
+
+	line := NewLine(NewVector(-240, -200), NewVector(230, 170))
+	expected = nil
+	for i, b := range bounds {
+		if segmentIntersectsRectangle(line.A, line.B, b) {
+			expected = append(expected, i)
+		}
+	}
+	found := hash.QuerySegment(line)
+	if len(expected) == 0 || !equalInts(sortedInts(found), expected) {
+		t.Error("segment query should match a linear scan")
+	}
+}
+
+func TestSpatialHashZeroCellSize(t *testing.T) {
+	hash := NewSpatialHash(0)
+	hash.Insert(1, NewRectangle(0, 0, 2, 2))
+	if found := hash.QueryRectangle(NewRectangle(1, 1, 1, 1)); 1 != len(found) {
+		t.Error("zero cell size should fall back to a usable grid")
+	}
+}
+
+func TestSpatialHashPairs(t *testing.T) {
+	hash := NewSpatialHash(10)
+	hash.Insert("a", NewRectangle(0, 0, 25, 25))
+	hash.Insert("b", NewRectangle(20, 20, 25, 25))
+	hash.Insert("c", NewRectangle(100, 100, 5, 5))
+	hash.Insert("d", NewRectangle(30, 0, 5, 5))
+	pairs := hash.Pairs()
+	if len(pairs) != 1 {
+		t.Fatal("only overlapping items should be paired, once each")
+	}
+	if !((pairs[0][0] == "a" && pairs[0][1] == "b") || (pairs[0][0] == "b" && pairs[0][1] == "a")) {
+		t.Error("overlapping items should be paired")
+	}
+
+	hash.Update("c", NewRectangle(32, 2, 5, 5))
+	hash.Remove("a")
+	pairs = hash.Pairs()
+	if len(pairs) != 1 || hash.Len() != 3 {
+		t.Error("moved items should be paired at their new position")
+	}
+	if len(hash.QueryRectangle(NewRectangle(0, 0, 1, 1))) != 0 {
+		t.Error("removed items should not be found")
+	}
+}
+
+func TestSegmentIntersectsRectangle(t *testing.T) {
+	rect := NewRectangle(0, 0, 10, 10)
+	if !segmentIntersectsRectangle(NewVector(-5, 5), NewVector(15, 5), rect) {
+		t.Error("segment crossing the rectangle should intersect")
+	}
+	if segmentIntersectsRectangle(NewVector(-5, 5), NewVector(-1, 5), rect) {
+		t.Error("segment stopping short should not intersect")
+	}
+	if segmentIntersectsRectangle(NewVector(-5, 8), NewVector(8, 21), rect) {
+		t.Error("segment passing the corner should not intersect")
+	}
+}
@@ -0,0 +1,286 @@
+package geo2
+
+import "math"
+
+// Impact describes the first contact of a moving shape with an obstacle
+type Impact struct {
+	// Time is the fraction of the velocity (0-1) travelled
+	// before the shapes touch
+	Time float64
+	// Normal is the unit surface normal at the contact, pointing
+	// out of the obstacle towards the moving shape. If the shapes
+	// already overlap it is the opposite of the velocity
+	Normal *Vector
+	// Point is where the shapes touch
+	Point *Vector
+}
+
+// SweepPointLine returns the impact of a point moving by the given
+// velocity against the given line segment, or nil if it is not hit
+func SweepPointLine(point, velocity *Vector, line *Line) *Impact {
+	return sweepPoint(point, velocity, nil, []*Line{line}, nil, 0)
+}
+
+// SweepPointRectangle returns the impact of a point moving by the
+// given velocity against the given rectangle, or nil if it is not hit
+func SweepPointRectangle(point, velocity *Vector, rect *Rectangle) *Impact {
+	return SweepPointPath(point, velocity, rect.ToPath())
+}
+
+// SweepPointPath returns the impact of a point moving by the given
+// velocity against the given convex path, or nil if it is not hit
+func SweepPointPath(point, velocity *Vector, path *Path) *Impact {
+	poly := counterClockwise(*path)
+	return sweepPoint(point, velocity, poly, nil, nil, 0)
+}
+
+// SweepCircleLine returns the impact of a circle moving by the given
+// velocity against the given line segment, or nil if it is not hit
+func SweepCircleLine(circle *Circle, velocity *Vector, line *Line) *Impact {
+	impact := sweepPoint(circle.Center, velocity, nil, []*Line{line}, []*Vector{line.A, line.B}, circle.Radius)
+	return circleContact(impact, circle)
+}
+
+// SweepCircleRectangle returns the impact of a circle moving by the
+// given velocity against the given rectangle, or nil if it is not hit
+func SweepCircleRectangle(circle *Circle, velocity *Vector, rect *Rectangle) *Impact {
+	return SweepCirclePath(circle, velocity, rect.ToPath())
+}
+
+// SweepCirclePath returns the impact of a circle moving by the given
+// velocity against the given convex path, or nil if it is not hit
+func SweepCirclePath(circle *Circle, velocity *Vector, path *Path) *Impact {
+	poly := counterClockwise(*path)
+	impact := sweepPoint(circle.Center, velocity, poly, nil, poly, circle.Radius)
+	return circleContact(impact, circle)
+}
+
+// SweepRectangleLine returns the impact of a rectangle moving by the
+// given velocity against the given line segment, or nil if it is not hit
+func SweepRectangleLine(rect *Rectangle, velocity *Vector, line *Line) *Impact {
+	return sweepRectangle(rect, velocity, []*Vector{line.A, line.B})
+}
+
+// SweepRectangleRectangle returns the impact of a rectangle moving by the
+// given velocity against another rectangle, or nil if it is not hit
+func SweepRectangleRectangle(rect *Rectangle, velocity *Vector, other *Rectangle) *Impact {
+	return sweepRectangle(rect, velocity, other.Corners())
+}
+
+// SweepRectanglePath returns the impact of a rectangle moving by the
+// given velocity against the given convex path, or nil if it is not hit
+func SweepRectanglePath(rect *Rectangle, velocity *Vector, path *Path) *Impact {
+	return sweepRectangle(rect, velocity, *path)
+}
+
+// sweepRectangle sweeps the center of the rectangle against the
+// minkowski sum of the obstacle and the (mirrored) rectangle
+func sweepRectangle(rect *Rectangle, velocity *Vector, obstacle []*Vector) *Impact {
+	r := rect.Clone().Normalize()
+	center := r.Center()
+	sum := Path{}
+	for _, p := range obstacle {
+		for _, corner := range r.Corners() {
+			sum = append(sum, p.Clone().Add(center).Sub(corner))
+		}
+	}
+	impact := sweepPoint(center, velocity, *sum.ConvexHull(), nil, nil, 0)
+	if nil == impact || impact.Time == 0 {
+		return impact
+	}
+
+	// the contact is the middle of the obstacle vertices touching
+	// the rectangle and the rectangle corners touching the obstacle
+	moved := r.Clone()
+	moved.X += velocity.X * impact.Time
+	moved.Y += velocity.Y * impact.Time
+	epsilon := 1e-9 * math.Max(1, moved.Width+moved.Height)
+	edges := pathEdges((*Path)(&obstacle), len(obstacle) > 2)
+	var touching []*Vector
+	for _, p := range obstacle {
+		if moved.DistanceTo(p) <= epsilon {
+			touching = append(touching, p)
+		}
+	}
+	for _, corner := range moved.Corners() {
+		for _, edge := range edges {
+			if edge.DistanceToPoint(corner, true) <= epsilon {
+				touching = append(touching, corner)
+				break
+			}
+		}
+	}
+	if 0 == len(touching) {
+		impact.Point = supportPoint(moved.Corners(), impact.Normal.Clone().Negate())
+		return impact
+	}
+	// use the middle of the span of touching points along the contact
+	tangent := NewVector(-impact.Normal.Y, impact.Normal.X)
+	min, max := projectPoints(touching, tangent)
+	offset := (min+max)*0.5 - touching[0].Dot(tangent)
+	impact.Point = touching[0].Clone().Add(tangent.MultiplyScalar(offset))
+	return impact
+}
+
+// circleContact moves the contact point of an impact
+// found for the center of a circle onto its edge
+func circleContact(impact *Impact, circle *Circle) *Impact {
+	if nil != impact && impact.Time > 0 {
+		impact.Point.Sub(impact.Normal.Clone().MultiplyScalar(circle.Radius))
+	}
+	return impact
+}
+
+// sweepPoint finds the first impact of a point moving by the given
+// velocity against a convex polygon (wound counter clockwise) expanded
+// by radius, along with any two sided line segments and the circles
+// of the given radius around the given corners
+func sweepPoint(point, velocity *Vector, poly []*Vector, lines []*Line, corners []*Vector, radius float64) *Impact {
+	speed := velocity.Length()
+	if 0 == speed {
+		return nil
+	}
+	dir := velocity.Clone().DivideScalar(speed)
+	stop := dir.Clone().Negate()
+
+	if len(poly) >= 3 && convexContains(poly, point, radius) {
+		return &Impact{0, stop, point.Clone()}
+	}
+
+	best := math.Inf(1)
+	var normal *Vector
+	consider := func(dist float64, n *Vector) {
+		if dist <= speed && dist < best {
+			best, normal = dist, n
+		}
+	}
+
+	for i := 0; len(poly) >= 3 && i < len(poly); i++ {
+		a, b := poly[i], poly[(i+1)%len(poly)]
+		n := edgeNormal(a, b)
+		if 0 == n.LengthSqd() || n.Dot(dir) >= 0 {
+			continue
+		}
+		n.Normalize()
+		offset := n.Clone().MultiplyScalar(radius)
+		if d, ok := raySegment(point, dir, a.Clone().Add(offset), b.Clone().Add(offset)); ok {
+			consider(d, n)
+		}
+	}
+	for _, line := range lines {
+		n := edgeNormal(line.A, line.B)
+		if 0 == n.LengthSqd() {
+			continue
+		}
+		n.Normalize()
+		if n.Dot(dir) > 0 {
+			n.Negate()
+		}
+		offset := n.Clone().MultiplyScalar(radius)
+		if d, ok := raySegment(point, dir, line.A.Clone().Add(offset), line.B.Clone().Add(offset)); ok {
+			consider(d, n)
+		}
+		// a point touching a line at the start is already in contact
+		if 0 == radius && 0 == line.CrossWithPoint(point) && line.DistanceToPoint(point, true) == 0 {
+			return &Impact{0, stop, point.Clone()}
+		}
+	}
+	for _, c := range corners {
+		if point.Clone().Sub(c).LengthSqd() < radius*radius {
+			return &Impact{0, stop, point.Clone()}
+		}
+		if d, ok := rayCircle(point, dir, c, radius); ok {
+			consider(d, point.Clone().Add(dir.Clone().MultiplyScalar(d)).Sub(c).Normalize())
+		}
+	}
+
+	if nil == normal {
+		return nil
+	}
+	return &Impact{
+		Time:   best / speed,
+		Normal: normal,
+		Point:  point.Clone().Add(dir.Clone().MultiplyScalar(best)),
+	}
+}
+
+// convexContains returns true if the given point is strictly inside
+// the counter clockwise convex polygon expanded by the given radius
+func convexContains(poly []*Vector, point *Vector, radius float64) bool {
+	inside := true
+	nearest := math.Inf(1)
+	for i := range poly {
+		edge := NewLine(poly[i], poly[(i+1)%len(poly)])
+		if edgeNormal(edge.A, edge.B).Dot(point.Clone().Sub(edge.A)) >= 0 {
+			inside = false
+		}
+		nearest = math.Min(nearest, edge.DistanceToPoint(point, true))
+	}
+	return inside || nearest < radius
+}
+
+// rayCircle returns the distance along the ray (with a unit direction)
+// at which it enters the given circle, if it does
+func rayCircle(origin, dir, center *Vector, radius float64) (float64, bool) {
+	m := origin.Clone().Sub(center)
+	b := m.Dot(dir)
+	c := m.LengthSqd() - radius*radius
+	if c > 0 && b > 0 {
+		return 0, false
+	}
+	disc := b*b - c
+	if disc < 0 {
+		return 0, false
+	}
+	t := -b - math.Sqrt(disc)
+	if t < 0 {
+		t = 0
+	}
+	return t, true
+}
+
+// TimeOfImpact finds when two convex shapes moving with the given
+// velocities first touch (within tolerance) using conservative
+// advancement, returning nil if they do not touch during the motion.
+// The impact normal points from b towards a and the point lies on b.
+// If the shapes are still approaching after the iteration limit, the
+// last (conservative) estimate is returned
+func TimeOfImpact(a Support, velocityA *Vector, b Support, velocityB *Vector, tolerance float64) *Impact {
+	if tolerance <= 0 {
+		tolerance = 1e-6
+	}
+	relative := velocityA.Clone().Sub(velocityB)
+	t := 0.0
+	var last *Impact
+	for iter := 0; iter < 100; iter++ {
+		movedA := &TransformedShape{a, TranslationMatrix(velocityA.X*t, velocityA.Y*t)}
+		movedB := &TransformedShape{b, TranslationMatrix(velocityB.X*t, velocityB.Y*t)}
+		result := GJK(movedA, movedB)
+		if result.Overlap {
+			if nil != last {
+				// the distance of curved shapes is only approximate,
+				// so the last step may just overshoot the contact
+				return last
+			}
+			stop := relative.Clone().Negate()
+			if 0 != stop.LengthSqd() {
+				stop.Normalize()
+			}
+			return &Impact{0, stop, movedB.Support(relative)}
+		}
+		normal := result.PointA.Clone().Sub(result.PointB).DivideScalar(result.Distance)
+		last = &Impact{t, normal, result.PointB}
+		if result.Distance <= tolerance {
+			return last
+		}
+		closing := -relative.Dot(normal)
+		if closing <= 0 {
+			return nil
+		}
+		t += (result.Distance - tolerance*0.5) / closing
+		if t > 1 {
+			return nil
+		}
+	}
+	return last
+}
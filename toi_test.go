@@ -0,0 +1,79 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func checkImpact(t *testing.T, impact *Impact, time float64, normal, point *Vector) {
+	t.Helper()
+	if nil == impact {
+		t.Error("expected an impact")
+		return
+	}
+	if math.Abs(impact.Time-time) > 1e-6 ||
+		!impact.Normal.CloseEnough(normal, 0.0001) ||
+		!impact.Point.CloseEnough(point, 0.0001) {
+		t.Error("impact should have the expected time, normal and point")
+	}
+}
+
+func TestSweepAgainstLine(t *testing.T) {
+	wall := NewLine(NewVector(5, -1), NewVector(5, 1))
+	velocity := NewVector(10, 0)
+	checkImpact(t, SweepPointLine(NewVector(0, 0), velocity, wall),
+		0.5, NewVector(-1, 0), NewVector(5, 0))
+	checkImpact(t, SweepCircleLine(NewCircle(NewVector(0, 0), 1), velocity, wall),
+		0.4, NewVector(-1, 0), NewVector(5, 0))
+	checkImpact(t, SweepCircleLine(NewCircle(NewVector(0, 1.5), 1), velocity, wall),
+		(5-math.Sqrt(0.75))/10, NewVector(-math.Sqrt(0.75), 0.5), NewVector(5, 1))
+	checkImpact(t, SweepRectangleLine(NewRectangle(0, -0.5, 1, 1), velocity, wall),
+		0.4, NewVector(-1, 0), NewVector(5, 0))
+	if nil != SweepPointLine(NewVector(0, 2), velocity, wall) {
+		t.Error("point passing the end of the wall should not hit it")
+	}
+	if nil != SweepPointLine(NewVector(0, 0), NewVector(4, 0), wall) {
+		t.Error("point stopping short of the wall should not hit it")
+	}
+}
+
+func TestSweepAgainstRectangle(t *testing.T) {
+	box := NewRectangle(5, 0, 2, 2)
+	checkImpact(t, SweepRectangleRectangle(NewRectangle(0, 0, 2, 2), NewVector(10, 0), box),
+		0.3, NewVector(-1, 0), NewVector(5, 1))
+	checkImpact(t, SweepCircleRectangle(NewCircle(NewVector(6, -5), 1), NewVector(0, 10), box),
+		0.4, NewVector(0, -1), NewVector(6, 0))
+	checkImpact(t, SweepPointRectangle(NewVector(6, 10), NewVector(0, -10), box),
+		0.8, NewVector(0, 1), NewVector(6, 2))
+	impact := SweepPointRectangle(NewVector(6, 1), NewVector(0, -10), box)
+	if nil == impact || 0 != impact.Time || !impact.Normal.Compare(NewVector(0, 1)) {
+		t.Error("point starting inside should hit immediately")
+	}
+}
+
+func TestSweepAgainstPath(t *testing.T) {
+	// a thin wall which a fast point would tunnel through
+	wall := &Path{NewVector(10, -5), NewVector(10.01, -5), NewVector(10.01, 5), NewVector(10, 5)}
+	checkImpact(t, SweepPointPath(NewVector(0, 0), NewVector(100, 0), wall),
+		0.1, NewVector(-1, 0), NewVector(10, 0))
+	tri := &Path{NewVector(0, 0), NewVector(4, 0), NewVector(0, 4)}
+	checkImpact(t, SweepCirclePath(NewCircle(NewVector(5, 5), 1), NewVector(-5, -5), tri),
+		(math.Sqrt(50)-math.Sqrt(8)-1)/math.Sqrt(50), NewVector(math.Sqrt(0.5), math.Sqrt(0.5)),
+		NewVector(2, 2))
+	checkImpact(t, SweepRectanglePath(NewRectangle(-1, 5, 2, 2), NewVector(0, -10), tri),
+		0.1, NewVector(0, 1), NewVector(0, 4))
+}
+
+func TestTimeOfImpact(t *testing.T) {
+	circle := NewCircle(NewVector(0, 0), 1)
+	box := NewRectangle(5, -1, 2, 2)
+	impact := TimeOfImpact(circle, NewVector(10, 0), box, NewVector(0, 0), 1e-6)
+	checkImpact(t, impact, 0.4, NewVector(-1, 0), NewVector(5, 0))
+
+	impact = TimeOfImpact(circle, NewVector(5, 0), box, NewVector(-5, 0), 1e-6)
+	checkImpact(t, impact, 0.4, NewVector(-1, 0), NewVector(3, 0))
+
+	if nil != TimeOfImpact(circle, NewVector(0, 10), box, NewVector(0, 0), 0) {
+		t.Error("shapes moving apart should not collide")
+	}
+}
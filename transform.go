@@ -0,0 +1,19 @@
+package geo2
+
+// Transformable is implemented by any shape that
+// can be transformed by a Matrix3
+type Transformable interface {
+	// Transform applies the given matrix to this shape in place
+	Transform(m *Matrix3)
+	// Transformed returns a transformed copy of this
+	// shape, leaving the original untouched
+	Transformed(m *Matrix3) Transformable
+}
+
+// TransformAll applies the given matrix in place
+// to each of the given shapes
+func TransformAll(m *Matrix3, shapes ...Transformable) {
+	for _, shape := range shapes {
+		shape.Transform(m)
+	}
+}
@@ -0,0 +1,56 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPathTransform(t *testing.T) {
+	path := &Path{NewVector(0, 0), NewVector(1, 0), NewVector(1, 1)}
+	moved := path.Transformed(TranslationMatrix(2, 3)).(*Path)
+	if !(*moved)[2].Compare(NewVector(3, 4)) {
+		t.Error("path should be translated by the matrix")
+	}
+	if !(*path)[2].Compare(NewVector(1, 1)) {
+		t.Error("transformed copy should not modify the original")
+	}
+	path.Transform(ScaleMatrix(2, 2))
+	if !(*path)[1].Compare(NewVector(2, 0)) {
+		t.Error("path should be scaled in place")
+	}
+}
+
+func TestTriangleListTransformSharedPoints(t *testing.T) {
+	path := &Path{NewVector(0, 0), NewVector(2, 0), NewVector(2, 2), NewVector(0, 2)}
+	tris := path.Triangulate()
+	tris.Transform(TranslationMatrix(1, 0))
+	for _, tri := range *tris {
+		for _, point := range tri.Points {
+			if point.X < 1 || point.X > 3 {
+				t.Error("shared points should only be transformed once")
+			}
+		}
+	}
+}
+
+func TestRectangleTransform(t *testing.T) {
+	rect := NewRectangle(-1, -1, 2, 2)
+	bounds := rect.Transformed(RotationMatrix(math.Pi / 4)).(*Rectangle)
+	size := math.Sqrt(2) * 2
+	if math.Abs(bounds.Width-size) > 1e-9 || math.Abs(bounds.Height-size) > 1e-9 {
+		t.Error("rotated rectangle should grow to bound its corners")
+	}
+	corners := rect.TransformToPath(RotationMatrix(math.Pi / 2))
+	if !(*corners)[0].CloseEnough(NewVector(1, -1), 0.0001) {
+		t.Error("oriented corners should be rotated")
+	}
+}
+
+func TestTransformAll(t *testing.T) {
+	line := NewLine(NewVector(0, 0), NewVector(1, 0))
+	tri := NewTriangle([]*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(0, 1)})
+	TransformAll(TranslationMatrix(0, 5), line, tri)
+	if line.A.Y != 5 || tri.Points[2].Y != 6 {
+		t.Error("all shapes should be transformed")
+	}
+}
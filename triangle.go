@@ -1,28 +1,237 @@
 package geo2
 
+import "math"
+
 //Triangle represents a 2d triangle
 type Triangle struct {
   Points []*Vector
 }
 
-//NewTriangle creates a new triangle
-//object from the given points
+//NewTriangle creates a new triangle object from the
+//first three of the given points, or returns nil
+//if fewer than three points are given
 func NewTriangle(points []*Vector) *Triangle {
+  if len(points) < 3 {
+    return nil
+  }
   return &Triangle{
     points[0:3],
   }
 }
 
-//Contains returns true if the given point is within this triangle
+//Clone creates a copy of this triangle by value
+func (t *Triangle) Clone() *Triangle {
+  return NewTriangle([]*Vector{
+    t.Points[0].Clone(),
+    t.Points[1].Clone(),
+    t.Points[2].Clone(),
+  })
+}
+
+//Transform applies the given matrix to the points of this
+//triangle in place. Points shared with other shapes will
+//be moved for those shapes as well
+func (t *Triangle) Transform(m *Matrix3) {
+  for _, point := range t.Points {
+    point.MultiplyMatrix(m)
+  }
+}
+
+//Transformed returns a copy of this triangle
+//transformed by the given matrix
+func (t *Triangle) Transformed(m *Matrix3) Transformable {
+  clone := t.Clone()
+  clone.Transform(m)
+  return clone
+}
+
+//Contains returns true if the given point is within this
+//triangle or on one of its edges, regardless of winding
 func (t *Triangle) Contains(point *Vector) bool {
+  if 0 == t.Orientation() {
+    return t.ContainsTolerance(point, 0)
+  }
   cp1 := NewLine(t.Points[0], t.Points[1]).CrossWithPoint(point)
   cp2 := NewLine(t.Points[1], t.Points[2]).CrossWithPoint(point)
   cp3 := NewLine(t.Points[2], t.Points[0]).CrossWithPoint(point)
 
-  return ((cp1 < 0 && cp2 < 0 && cp3 < 0) ||
+  return ((cp1 <= 0 && cp2 <= 0 && cp3 <= 0) ||
     (cp1 >= 0 && cp2 >= 0 && cp3 >= 0))
 }
 
+//ContainsTolerance returns true if the given point is within
+//this triangle or no further than the tolerance from its edges
+func (t *Triangle) ContainsTolerance(point *Vector, tolerance float64) bool {
+  return t.ClosestPoint(point).Sub(point).Length() <= tolerance
+}
+
+//SignedArea returns the area of this triangle, which is
+//positive when its points are wound counter clockwise
+func (t *Triangle) SignedArea() float64 {
+  return orient(t.Points[0], t.Points[1], t.Points[2]) / 2
+}
+
+//Area returns the area of this triangle
+func (t *Triangle) Area() float64 {
+  return math.Abs(t.SignedArea())
+}
+
+//Orientation returns 1 if the points of this triangle are wound
+//counter clockwise, -1 if clockwise and 0 if they are collinear
+func (t *Triangle) Orientation() int {
+  area := t.SignedArea()
+  switch {
+  case area > 0:
+    return 1
+  case area < 0:
+    return -1
+  }
+  return 0
+}
+
+//Centroid returns the center of mass of this triangle
+func (t *Triangle) Centroid() *Vector {
+  return NewVector(
+    (t.Points[0].X+t.Points[1].X+t.Points[2].X)/3,
+    (t.Points[0].Y+t.Points[1].Y+t.Points[2].Y)/3,
+  )
+}
+
+//Barycentric returns the weights of each point of this triangle
+//which combine to give the given point. The weights sum to one and
+//are all positive when the point lies inside the triangle. Returns
+//nil if the triangle is degenerate
+func (t *Triangle) Barycentric(point *Vector) []float64 {
+  area := orient(t.Points[0], t.Points[1], t.Points[2])
+  if 0 == area {
+    return nil
+  }
+  u := orient(t.Points[1], t.Points[2], point) / area
+  v := orient(t.Points[2], t.Points[0], point) / area
+  return []float64{u, v, 1 - u - v}
+}
+
+//FromBarycentric returns the point given by weighting each
+//point of this triangle, or nil if fewer than three weights
+//are given
+func (t *Triangle) FromBarycentric(weights []float64) *Vector {
+  if len(weights) < 3 {
+    return nil
+  }
+  point := NewVector(0, 0)
+  for i, w := range weights[:3] {
+    point.Add(t.Points[i].Clone().MultiplyScalar(w))
+  }
+  return point
+}
+
+//EdgeLengths returns the length of each edge of
+//this triangle, where edge i starts at point i
+func (t *Triangle) EdgeLengths() []float64 {
+  lengths := make([]float64, 3)
+  for i := range lengths {
+    lengths[i] = t.Points[(i+1)%3].Clone().Sub(t.Points[i]).Length()
+  }
+  return lengths
+}
+
+//Angles returns the interior angle in radians
+//at each point of this triangle
+func (t *Triangle) Angles() []float64 {
+  angles := make([]float64, 3)
+  for i := range angles {
+    a := t.Points[(i+2)%3].Clone().Sub(t.Points[i])
+    b := t.Points[(i+1)%3].Clone().Sub(t.Points[i])
+    angles[i] = math.Abs(math.Atan2(a.Cross(b), a.Dot(b)))
+  }
+  return angles
+}
+
+//MinAngle returns the smallest interior angle of this triangle
+func (t *Triangle) MinAngle() float64 {
+  angles := t.Angles()
+  return math.Min(angles[0], math.Min(angles[1], angles[2]))
+}
+
+//Circumcircle returns the circle passing through each point of
+//this triangle, or nil if the triangle is degenerate
+func (t *Triangle) Circumcircle() *Circle {
+  center := circumcenter(t.Points[0], t.Points[1], t.Points[2])
+  if nil == center {
+    return nil
+  }
+  return NewCircle(center, center.Clone().Sub(t.Points[0]).Length())
+}
+
+//Incircle returns the largest circle which fits inside this
+//triangle, or nil if the triangle is degenerate
+func (t *Triangle) Incircle() *Circle {
+  lengths := t.EdgeLengths()
+  perimeter := lengths[0] + lengths[1] + lengths[2]
+  area := t.Area()
+  if 0 == area {
+    return nil
+  }
+  center := NewVector(0, 0)
+  for i, point := range t.Points[:3] {
+    // each point is weighted by the length of the opposite edge
+    center.Add(point.Clone().MultiplyScalar(lengths[(i+1)%3] / perimeter))
+  }
+  return NewCircle(center, 2*area/perimeter)
+}
+
+//AspectRatio returns the ratio of the circumradius of this
+//triangle to twice its inradius, which is 1 for an equilateral
+//triangle and grows as the triangle becomes thinner (infinite
+//when degenerate)
+func (t *Triangle) AspectRatio() float64 {
+  circum, in := t.Circumcircle(), t.Incircle()
+  if nil == circum || nil == in {
+    return math.Inf(1)
+  }
+  return circum.Radius / (2 * in.Radius)
+}
+
+//RadiusEdgeRatio returns the ratio of the circumradius of this
+//triangle to its shortest edge, which is 1/√3 for an equilateral
+//triangle and grows as its smallest angle shrinks (infinite
+//when degenerate)
+func (t *Triangle) RadiusEdgeRatio() float64 {
+  circum := t.Circumcircle()
+  if nil == circum {
+    return math.Inf(1)
+  }
+  lengths := t.EdgeLengths()
+  return circum.Radius / math.Min(lengths[0], math.Min(lengths[1], lengths[2]))
+}
+
+//ClosestPoint returns the point within this
+//triangle which is closest to the given point
+func (t *Triangle) ClosestPoint(point *Vector) *Vector {
+  if 0 != t.Orientation() && t.Contains(point) {
+    return point.Clone()
+  }
+  var best *Vector
+  bestDist := math.Inf(1)
+  for i := 0; i < 3; i++ {
+    candidate := NewLine(t.Points[i], t.Points[(i+1)%3]).ClosestPoint(point, true)
+    if dist := candidate.Clone().Sub(point).LengthSqd(); dist < bestDist {
+      best, bestDist = candidate, dist
+    }
+  }
+  return best
+}
+
+//ToPath returns a copy of the points of
+//this triangle as a closed path
+func (t *Triangle) ToPath() *Path {
+  path := make(Path, 3)
+  for i := range path {
+    path[i] = t.Points[i].Clone()
+  }
+  return &path
+}
+
 //ToFloat32Array returns a float32
 //array of the points in this triangle
 func (t *Triangle) ToFloat32Array() []float32 {
@@ -45,3 +254,56 @@ func (tris *TriangleList) ToFloat32Array() []float32 {
   }
   return floats
 }
+
+// Bounds returns the smallest rectangle containing every
+// triangle in this list, or nil if the list is empty
+func (tris *TriangleList) Bounds() *Rectangle {
+  points := make([]*Vector, 0, len(*tris)*3)
+  for _, tri := range *tris {
+    points = append(points, tri.Points...)
+  }
+  return NewRectangleFromPoints(points)
+}
+
+// Clone creates a copy of this list of triangles by value.
+// Points which are shared between triangles (such as those
+// created by Path.Triangulate) remain shared in the copy
+func (tris *TriangleList) Clone() *TriangleList {
+  clones := make(map[*Vector]*Vector)
+  clone := make(TriangleList, len(*tris))
+  for i, tri := range *tris {
+    points := make([]*Vector, 3)
+    for j, point := range tri.Points {
+      if _, ok := clones[point]; !ok {
+        clones[point] = point.Clone()
+      }
+      points[j] = clones[point]
+    }
+    clone[i] = NewTriangle(points)
+  }
+  return &clone
+}
+
+// Transform applies the given matrix to every triangle in
+// this list in place. Points shared between triangles are
+// only transformed once
+func (tris *TriangleList) Transform(m *Matrix3) {
+  seen := make(map[*Vector]bool)
+  for _, tri := range *tris {
+    for _, point := range tri.Points {
+      if seen[point] {
+        continue
+      }
+      seen[point] = true
+      point.MultiplyMatrix(m)
+    }
+  }
+}
+
+// Transformed returns a copy of this list of
+// triangles transformed by the given matrix
+func (tris *TriangleList) Transformed(m *Matrix3) Transformable {
+  clone := tris.Clone()
+  clone.Transform(m)
+  return clone
+}
@@ -1,6 +1,9 @@
 package geo2
 
-import "testing"
+import (
+  "math"
+  "testing"
+)
 
 func TestTriangleContains(t *testing.T) {
   tri := NewTriangle([]*Vector{
@@ -20,3 +23,82 @@ func TestTriangleContains(t *testing.T) {
     t.Error("triangle should not contain point")
   }
 }
+
+func TestTriangleConstruction(t *testing.T) {
+  if nil != NewTriangle([]*Vector{NewVector(0, 0), NewVector(1, 0)}) {
+    t.Error("triangle should not be created from two points")
+  }
+}
+
+func TestTriangleToPath(t *testing.T) {
+  tri := NewTriangle([]*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(0, 1)})
+  path := tri.ToPath()
+  (*path)[0].Set(5, 5)
+  if !tri.Points[0].Compare(NewVector(0, 0)) {
+    t.Error("changing the path should not change the triangle")
+  }
+}
+
+func TestTriangleContainsEdges(t *testing.T) {
+  ccw := NewTriangle([]*Vector{NewVector(0, 0), NewVector(2, 0), NewVector(0, 2)})
+  cw := NewTriangle([]*Vector{NewVector(0, 0), NewVector(0, 2), NewVector(2, 0)})
+  for _, point := range []*Vector{NewVector(1, 0), NewVector(1, 1), NewVector(0, 0)} {
+    if !ccw.Contains(point) || !cw.Contains(point) {
+      t.Error("points on the edge should be contained for both windings")
+    }
+  }
+  if cw.Contains(NewVector(1.1, 1)) || !cw.ContainsTolerance(NewVector(1.1, 1), 0.1) {
+    t.Error("point just outside should only be contained within a tolerance")
+  }
+  flat := NewTriangle([]*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(2, 0)})
+  if !flat.Contains(NewVector(1.5, 0)) || flat.Contains(NewVector(3, 0)) {
+    t.Error("degenerate triangle should only contain points on its segment")
+  }
+  if !ccw.ClosestPoint(NewVector(2, 2)).CloseEnough(NewVector(1, 1), 1e-9) {
+    t.Error("closest point should lie on the hypotenuse")
+  }
+}
+
+func TestTriangleMeasurements(t *testing.T) {
+  tri := NewTriangle([]*Vector{NewVector(0, 0), NewVector(4, 0), NewVector(0, 3)})
+  if 6 != tri.SignedArea() || 1 != tri.Orientation() {
+    t.Error("expected a counter clockwise area of 6")
+  }
+  if !tri.Centroid().CloseEnough(NewVector(4.0/3, 1), 1e-9) {
+    t.Error("unexpected centroid")
+  }
+
+  weights := tri.Barycentric(NewVector(1, 1))
+  if nil == weights || !tri.FromBarycentric(weights).CloseEnough(NewVector(1, 1), 1e-9) {
+    t.Error("barycentric weights should recreate the point")
+  }
+  if nil != tri.FromBarycentric([]float64{0.5, 0.5}) {
+    t.Error("fewer than three weights should not give a point")
+  }
+
+  circum := tri.Circumcircle()
+  if !circum.Center.CloseEnough(NewVector(2, 1.5), 1e-9) || 2.5 != circum.Radius {
+    t.Error("expected the circumcircle around the hypotenuse")
+  }
+  in := tri.Incircle()
+  if !in.Center.CloseEnough(NewVector(1, 1), 1e-9) || math.Abs(in.Radius-1) > 1e-9 {
+    t.Error("expected an incircle of radius 1 at (1, 1)")
+  }
+
+  angles := tri.Angles()
+  if math.Abs(angles[0]-math.Pi/2) > 1e-9 || math.Abs(angles[0]+angles[1]+angles[2]-math.Pi) > 1e-9 {
+    t.Error("unexpected angles")
+  }
+  if math.Abs(tri.AspectRatio()-1.25) > 1e-9 || math.Abs(tri.RadiusEdgeRatio()-2.5/3) > 1e-9 {
+    t.Error("unexpected quality")
+  }
+
+  equilateral := NewTriangle([]*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(0.5, math.Sqrt(3)/2)})
+  if math.Abs(equilateral.AspectRatio()-1) > 1e-9 {
+    t.Error("equilateral triangle should have an aspect ratio of 1")
+  }
+  flat := NewTriangle([]*Vector{NewVector(0, 0), NewVector(1, 0), NewVector(2, 0)})
+  if nil != flat.Circumcircle() || nil != flat.Barycentric(NewVector(0, 0)) || !math.IsInf(flat.AspectRatio(), 1) {
+    t.Error("degenerate triangle should have no circumcircle or barycentric weights")
+  }
+}
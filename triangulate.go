@@ -0,0 +1,225 @@
+package geo2
+
+import "math"
+
+// TriangulateWithHoles triangulates the area within this path
+// (treated as a closed loop) excluding the area within each of the
+// given holes. Holes are joined to the outer loop by bridges before
+// the result is ear clipped, so holes must lie entirely inside this
+// path and must not overlap each other. The winding of the path and
+// holes does not matter. Returns nil if the path has fewer than three
+// distinct points, or if no ear can be clipped from what remains (such
+// as when the holes break those rules)
+func (path *Path) TriangulateWithHoles(holes []*Path) *TriangleList {
+	outer := dedupeLoop(*path.Clone())
+	if len(outer) < 3 {
+		return nil
+	}
+	outer = counterClockwise(outer)
+
+	var loops [][]*Vector
+	for _, hole := range holes {
+		loop := dedupeLoop(*hole.Clone())
+		if len(loop) < 3 {
+			continue
+		}
+		// holes are wound the opposite way to the outer loop
+		loop = counterClockwise(loop)
+		for i, j := 0, len(loop)-1; i < j; i, j = i+1, j-1 {
+			loop[i], loop[j] = loop[j], loop[i]
+		}
+		loops = append(loops, loop)
+	}
+
+	// join the holes from right to left so that each bridge
+	// can reach either the outer loop or an already joined hole
+	for len(loops) > 0 {
+		next, nextX := 0, 0
+		for i, loop := range loops {
+			if m := rightmostPoint(loop); 0 == i || loop[m].X > loops[next][nextX].X {
+				next, nextX = i, m
+			}
+		}
+		hole := loops[next]
+		loops = append(loops[:next], loops[next+1:]...)
+		outer = bridgeHole(outer, hole, nextX, loops)
+	}
+
+	return earClip(outer)
+}
+
+// dedupeLoop removes consecutive duplicate points from the given loop
+func dedupeLoop(points []*Vector) []*Vector {
+	result := make([]*Vector, 0, len(points))
+	for _, p := range points {
+		if len(result) > 0 && result[len(result)-1].Compare(p) {
+			continue
+		}
+		result = append(result, p)
+	}
+	for len(result) > 1 && result[0].Compare(result[len(result)-1]) {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+// rightmostPoint returns the index of the point with the largest x
+func rightmostPoint(points []*Vector) int {
+	best := 0
+	for i, p := range points {
+		if p.X > points[best].X {
+			best = i
+		}
+	}
+	return best
+}
+
+// bridgeHole joins the given (clockwise) hole into the (counter
+// clockwise) outer loop through the hole's point at index m, picking the
+// closest outer point which can be seen from it without crossing either
+// loop or any of the remaining holes
+func bridgeHole(outer, hole []*Vector, m int, others [][]*Vector) []*Vector {
+	start := hole[m]
+	best, bestDist := -1, 0.0
+	for i, v := range outer {
+		dist := v.Clone().Sub(start).LengthSqd()
+		if -1 != best && dist >= bestDist {
+			continue
+		}
+		prev := outer[(i+len(outer)-1)%len(outer)]
+		next := outer[(i+1)%len(outer)]
+		if !inCone(prev, v, next, start) {
+			continue
+		}
+		if loopBlocks(outer, start, v) || loopBlocks(hole, start, v) {
+			continue
+		}
+		blocked := false
+		for _, other := range others {
+			if loopBlocks(other, start, v) {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			best, bestDist = i, dist
+		}
+	}
+	if -1 == best {
+		// the hole cannot be reached and is most likely outside
+		// of the outer loop, so it is ignored rather than corrupting it
+		return outer
+	}
+
+	merged := make([]*Vector, 0, len(outer)+len(hole)+2)
+	merged = append(merged, outer[:best+1]...)
+	merged = append(merged, hole[m:]...)
+	merged = append(merged, hole[:m+1]...)
+	merged = append(merged, outer[best:]...)
+	return merged
+}
+
+// inCone returns true if the point lies within the interior
+// angle at v of a counter clockwise loop running prev, v, next
+func inCone(prev, v, next, point *Vector) bool {
+	leftOfIn := orient(prev, v, point) > 0
+	leftOfOut := orient(v, next, point) > 0
+	if orient(prev, v, next) >= 0 {
+		return leftOfIn && leftOfOut
+	}
+	return leftOfIn || leftOfOut
+}
+
+// loopBlocks returns true if any edge of the given loop properly
+// crosses the segment between a and b, ignoring edges which
+// share an endpoint with the segment
+func loopBlocks(loop []*Vector, a, b *Vector) bool {
+	for i, p := range loop {
+		q := loop[(i+1)%len(loop)]
+		if p.Compare(a) || p.Compare(b) || q.Compare(a) || q.Compare(b) {
+			continue
+		}
+		if segmentsCross(a, b, p, q) {
+			return true
+		}
+	}
+	return false
+}
+
+// earClip triangulates the given counter clockwise loop, which may
+// touch itself at the bridges created by bridgeHole. Returns nil if
+// the loop runs out of ears before every corner has been clipped
+func earClip(loop []*Vector) *TriangleList {
+	triangles := TriangleList{}
+	indices := make([]int, len(loop))
+	for i := range indices {
+		indices[i] = i
+	}
+	for len(indices) > 3 {
+		n := len(indices)
+		clipped := false
+		for i := 0; i < n; i++ {
+			prev := loop[indices[(i+n-1)%n]]
+			curr := loop[indices[i]]
+			next := loop[indices[(i+1)%n]]
+			if orient(prev, curr, next) <= 0 || !isEar(loop, indices, prev, curr, next) {
+				continue
+			}
+			triangles = append(triangles, NewTriangle([]*Vector{prev, curr, next}))
+			indices = append(indices[:i], indices[i+1:]...)
+			clipped = true
+			break
+		}
+		if clipped {
+			continue
+		}
+		// no ear could be found, which happens with collinear or
+		// degenerate points. Those can be dropped without losing any
+		// area, but any other corner means the loop is not simple
+		flat := -1
+		for i := 0; i < n && -1 == flat; i++ {
+			if flatCorner(loop[indices[(i+n-1)%n]], loop[indices[i]], loop[indices[(i+1)%n]]) {
+				flat = i
+			}
+		}
+		if -1 == flat {
+			return nil
+		}
+		indices = append(indices[:flat], indices[flat+1:]...)
+	}
+	a, b, c := loop[indices[0]], loop[indices[1]], loop[indices[2]]
+	switch {
+	case flatCorner(a, b, c):
+	case orient(a, b, c) > 0:
+		triangles = append(triangles, NewTriangle([]*Vector{a, b, c}))
+	default:
+		return nil
+	}
+	return &triangles
+}
+
+// flatCorner returns true if the corner at b is in line
+// with a and c to within rounding error
+func flatCorner(a, b, c *Vector) bool {
+	sides := b.Clone().Sub(a).Length() * c.Clone().Sub(b).Length()
+	return math.Abs(orient(a, b, c)) <= 1e-12*sides
+}
+
+// isEar returns true if no reflex point of the remaining
+// loop lies within the triangle prev, curr, next
+func isEar(loop []*Vector, indices []int, prev, curr, next *Vector) bool {
+	n := len(indices)
+	for i := 0; i < n; i++ {
+		p := loop[indices[i]]
+		if p.Compare(prev) || p.Compare(curr) || p.Compare(next) {
+			continue
+		}
+		if orient(loop[indices[(i+n-1)%n]], p, loop[indices[(i+1)%n]]) > 0 {
+			continue
+		}
+		if orient(prev, curr, p) >= 0 && orient(curr, next, p) >= 0 && orient(next, prev, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
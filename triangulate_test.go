@@ -0,0 +1,54 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func trianglesArea(tris *TriangleList) float64 {
+	area := 0.0
+	for _, tri := range *tris {
+		area += math.Abs(signedArea(tri.Points))
+	}
+	return area
+}
+
+func TestTriangulateWithHoles(t *testing.T) {
+	outer := NewRectangle(0, 0, 10, 10).ToPath()
+	holes := []*Path{
+		NewRectangle(2, 2, 2, 2).ToPath(),
+		{NewVector(6, 6), NewVector(8, 6), NewVector(7, 8)},
+	}
+	tris := outer.TriangulateWithHoles(holes)
+	if nil == tris {
+		t.Fatal("expected a triangulation")
+	}
+	if area := trianglesArea(tris); math.Abs(area-(100-4-2)) > 1e-9 {
+		t.Error("triangles should cover the area outside the holes")
+	}
+	for _, point := range []*Vector{NewVector(3, 3), NewVector(7, 6.5)} {
+		for _, tri := range *tris {
+			if orient(tri.Points[0], tri.Points[1], point) > 0 &&
+				orient(tri.Points[1], tri.Points[2], point) > 0 &&
+				orient(tri.Points[2], tri.Points[0], point) > 0 {
+				t.Error("points inside a hole should not be covered")
+			}
+		}
+	}
+
+	concave := &Path{NewVector(0, 0), NewVector(4, 0), NewVector(4, 4), NewVector(2, 1), NewVector(0, 4)}
+	if tris := concave.TriangulateWithHoles(nil); 3 != len(*tris) || math.Abs(trianglesArea(tris)-10) > 1e-9 {
+		t.Error("concave path should be covered by 3 triangles")
+	}
+}
+
+func TestTriangulateDegenerate(t *testing.T) {
+	collinear := &Path{NewVector(0, 0), NewVector(1, 0), NewVector(2, 0), NewVector(3, 0), NewVector(3, 3)}
+	if tris := collinear.TriangulateWithHoles(nil); nil == tris || math.Abs(trianglesArea(tris)-4.5) > 1e-9 {
+		t.Error("collinear points should not lose any area")
+	}
+	bowtie := &Path{NewVector(0, 0), NewVector(2, 2), NewVector(2, 0), NewVector(0, 2)}
+	if nil != bowtie.TriangulateWithHoles(nil) {
+		t.Error("self intersecting path should not be triangulated")
+	}
+}
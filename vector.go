@@ -91,6 +91,30 @@ func (v *Vector) MultiplyMatrix(m *Matrix3) *Vector {
   return v
 }
 
+//MultiplyMatrixProjective multiplies this vector by the given
+//matrix as a homogeneous point (x, y, 1), including the bottom
+//row of the matrix, and divides the result by its w component.
+//Points which map to infinity (w = 0) end up with infinite values
+func (v *Vector) MultiplyMatrixProjective(m *Matrix3) *Vector {
+  x := v.X
+  w := x*m[2][0] + v.Y*m[2][1] + m[2][2]
+  v.X = (x*m[0][0] + v.Y*m[0][1] + m[0][2]) / w
+  v.Y = (x*m[1][0] + v.Y*m[1][1] + m[1][2]) / w
+  return v
+}
+
+//Transform applies the given matrix to this vector
+//in place (see MultiplyMatrix)
+func (v *Vector) Transform(m *Matrix3) {
+  v.MultiplyMatrix(m)
+}
+
+//Transformed returns a copy of this vector
+//multiplied by the given matrix
+func (v *Vector) Transformed(m *Matrix3) Transformable {
+  return v.Clone().MultiplyMatrix(m)
+}
+
 //MultiplyScalar multiplies this vector by the given scalar value
 func (v *Vector) MultiplyScalar(value float64) *Vector {
   v.X *= value
@@ -129,7 +153,7 @@ func (v *Vector) Cross(v2 *Vector) float64 {
 //Dot find the dot product of this
 //vector and the given vector
 func (v *Vector) Dot(v2 *Vector) float64 {
-  return v.X*v2.Y + v.Y*v2.X
+  return v.X*v2.X + v.Y*v2.Y
 }
 
 //Negate negate this vector (make it it's exact opposite)
@@ -15,6 +15,9 @@ func TestVectorMath(t *testing.T) {
   if !v1.Clone().Sub(v2).Compare(NewVector(9, 4)) {
     t.Error("vector subtraction should work")
   }
+  if 76 != v1.Dot(v2) {
+    t.Error("vector dot product should work")
+  }
 }
 
 func TestVectorRotationConversion(t *testing.T) {
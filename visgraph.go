@@ -0,0 +1,316 @@
+package geo2
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// VisibilityGraph connects the corners of polygonal obstacles which
+// can see each other, so that shortest paths between any two points
+// can be found by searching the graph
+type VisibilityGraph struct {
+	// Obstacles are the (possibly inflated) obstacle
+	// loops, all wound counter clockwise
+	Obstacles []*Path
+	// Nodes are the convex obstacle corners in the graph
+	Nodes []*Vector
+	// Edges lists the nodes visible from each node
+	Edges [][]int
+
+	corners   []visCorner
+	tolerance float64
+}
+
+// visCorner records where a node lies on the obstacles
+type visCorner struct {
+	obstacle, index int
+}
+
+// NewVisibilityGraph creates a visibility graph around the given
+// obstacles (treated as closed loops), inflated by the radius of the
+// agent moving between them. Inflated corners are mitred, and
+// bevelled when sharp, so that they contain every point within the
+// radius of the original obstacle
+func NewVisibilityGraph(obstacles []*Path, radius float64) *VisibilityGraph {
+	graph := &VisibilityGraph{}
+	var all []*Vector
+	for _, obstacle := range obstacles {
+		points := dedupeLoop(*obstacle.Clone())
+		if len(points) < 3 {
+			continue
+		}
+		points = counterClockwise(points)
+		if radius > 0 {
+			points = inflateLoop(points, radius)
+		}
+		loop := Path(points)
+		graph.Obstacles = append(graph.Obstacles, &loop)
+		all = append(all, points...)
+	}
+	if len(all) > 0 {
+		bounds := NewRectangleFromPoints(all)
+		graph.tolerance = 1e-9 * (1 + math.Max(bounds.Width, bounds.Height))
+	}
+
+	// only convex corners can lie on a shortest path, and only
+	// when they are not buried inside another obstacle
+	for o, obstacle := range graph.Obstacles {
+		points := *obstacle
+		for i, p := range points {
+			prev := points[(i+len(points)-1)%len(points)]
+			next := points[(i+1)%len(points)]
+			if orient(prev, p, next) <= 0 || graph.blocked(p, o) {
+				continue
+			}
+			graph.Nodes = append(graph.Nodes, p)
+			graph.corners = append(graph.corners, visCorner{o, i})
+		}
+	}
+	graph.Edges = make([][]int, len(graph.Nodes))
+	for i := range graph.Nodes {
+		for j := i + 1; j < len(graph.Nodes); j++ {
+			if graph.visible(graph.Nodes[i], graph.Nodes[j], &graph.corners[i], &graph.corners[j]) {
+				graph.Edges[i] = append(graph.Edges[i], j)
+				graph.Edges[j] = append(graph.Edges[j], i)
+			}
+		}
+	}
+	return graph
+}
+
+// inflateLoop offsets the given counter clockwise loop outwards
+func inflateLoop(points []*Vector, radius float64) []*Vector {
+	result := make([]*Vector, 0, len(points))
+	for i, p := range points {
+		prev := points[(i+len(points)-1)%len(points)]
+		next := points[(i+1)%len(points)]
+		n1 := edgeNormal(prev, p).Normalize()
+		n2 := edgeNormal(p, next).Normalize()
+		cos := n1.Dot(n2)
+		if orient(prev, p, next) > 0 && cos < 0 {
+			// sharp corners are bevelled across the corners of the
+			// square around the point to avoid long mitres
+			t1 := p.Clone().Sub(prev).Normalize()
+			t2 := next.Clone().Sub(p).Normalize()
+			result = append(result,
+				n1.Clone().Add(t1).MultiplyScalar(radius).Add(p),
+				n2.Clone().Sub(t2).MultiplyScalar(radius).Add(p))
+			continue
+		}
+		miter := n1.Add(n2).MultiplyScalar(radius / (1 + cos))
+		result = append(result, miter.Add(p))
+	}
+	return result
+}
+
+// blocked returns true if the point lies strictly
+// inside any obstacle other than the one given
+func (graph *VisibilityGraph) blocked(point *Vector, skip int) bool {
+	for o, obstacle := range graph.Obstacles {
+		if o != skip && obstacle.Contains(point) && !onLoop(*obstacle, point) {
+			return true
+		}
+	}
+	return false
+}
+
+// onLoop returns true if the point lies on an edge of the given loop
+func onLoop(points []*Vector, point *Vector) bool {
+	for i, a := range points {
+		b := points[(i+1)%len(points)]
+		if 0 == orient(a, b, point) && onSegment(a, b, point) {
+			return true
+		}
+	}
+	return false
+}
+
+// Visible returns true if the straight line between the
+// two points does not pass through any of the obstacles
+func (graph *VisibilityGraph) Visible(a, b *Vector) bool {
+	return graph.visible(a, b, graph.cornerAt(a), graph.cornerAt(b))
+}
+
+// cornerAt returns the obstacle corner at the given point, if any
+func (graph *VisibilityGraph) cornerAt(point *Vector) *visCorner {
+	for o, obstacle := range graph.Obstacles {
+		for i, p := range *obstacle {
+			if p.Compare(point) {
+				return &visCorner{o, i}
+			}
+		}
+	}
+	return nil
+}
+
+// visible checks the line between a and b, which
+// may be the given corners of the obstacles
+func (graph *VisibilityGraph) visible(a, b *Vector, cornerA, cornerB *visCorner) bool {
+	if a.Compare(b) {
+		return true
+	}
+	// leaving a corner must not head into its obstacle
+	for _, c := range []struct {
+		corner *visCorner
+		target *Vector
+	}{{cornerA, b}, {cornerB, a}} {
+		if nil == c.corner {
+			continue
+		}
+		points := *graph.Obstacles[c.corner.obstacle]
+		i := c.corner.index
+		prev := points[(i+len(points)-1)%len(points)]
+		next := points[(i+1)%len(points)]
+		if inCone(prev, points[i], next, c.target) {
+			return false
+		}
+	}
+	// split the line wherever it touches an obstacle edge, so that
+	// lines passing through corners are checked either side of them
+	cuts := []float64{0, 1}
+	dir := b.Clone().Sub(a)
+	lengthSqd := dir.LengthSqd()
+	for _, obstacle := range graph.Obstacles {
+		points := *obstacle
+		for i, p := range points {
+			q := points[(i+1)%len(points)]
+			d1, d2 := orient(p, q, a), orient(p, q, b)
+			d3, d4 := orient(a, b, p), orient(a, b, q)
+			if d1*d2 < 0 && d3*d4 < 0 {
+				return false
+			}
+			for _, touch := range []struct {
+				point *Vector
+				side  float64
+			}{{p, d3}, {q, d4}} {
+				if 0 == touch.side && onSegment(a, b, touch.point) {
+					cuts = append(cuts, touch.point.Clone().Sub(a).Dot(dir)/lengthSqd)
+				}
+			}
+		}
+	}
+	sort.Float64s(cuts)
+	for i := 1; i < len(cuts); i++ {
+		if cuts[i]-cuts[i-1] <= 0 {
+			continue
+		}
+		middle := NewLine(a, b).GetPosition((cuts[i-1] + cuts[i]) / 2)
+		for _, obstacle := range graph.Obstacles {
+			if insideLoop(*obstacle, middle, graph.tolerance) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// insideLoop returns true if the point lies inside the given
+// loop and further than the tolerance from its boundary
+func insideLoop(loop []*Vector, point *Vector, tolerance float64) bool {
+	path := Path(loop)
+	if !path.Contains(point) {
+		return false
+	}
+	for i, a := range loop {
+		if NewLine(a, loop[(i+1)%len(loop)]).DistanceToPoint(point, true) <= tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// ShortestPath returns the shortest path between the start and goal
+// points which avoids the obstacles, found with A* over the graph.
+// Returns nil if either point is inside an obstacle or the
+// goal cannot be reached
+func (graph *VisibilityGraph) ShortestPath(start, goal *Vector) *Path {
+	if graph.blocked(start, -1) || graph.blocked(goal, -1) {
+		return nil
+	}
+	startCorner, goalCorner := graph.cornerAt(start), graph.cornerAt(goal)
+	if graph.visible(start, goal, startCorner, goalCorner) {
+		return &Path{start.Clone(), goal.Clone()}
+	}
+
+	// the start and goal are added as the last two nodes
+	count := len(graph.Nodes)
+	from, to := count, count+1
+	node := func(i int) *Vector {
+		switch i {
+		case from:
+			return start
+		case to:
+			return goal
+		}
+		return graph.Nodes[i]
+	}
+	corner := func(i int) *visCorner {
+		switch i {
+		case from:
+			return startCorner
+		case to:
+			return goalCorner
+		}
+		return &graph.corners[i]
+	}
+	reachesGoal := make([]bool, count)
+	for i := range graph.Nodes {
+		reachesGoal[i] = graph.visible(graph.Nodes[i], goal, corner(i), goalCorner)
+	}
+
+	cost := make([]float64, count+2)
+	came := make([]int, count+2)
+	closed := make([]bool, count+2)
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		came[i] = -1
+	}
+	cost[from] = 0
+	queue := &priorityQueue{{start.Clone().Sub(goal).Length(), from}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(priorityItem).value.(int)
+		if closed[current] {
+			continue
+		}
+		if current == to {
+			break
+		}
+		closed[current] = true
+
+		var neighbours []int
+		if current == from {
+			for i := range graph.Nodes {
+				if graph.visible(start, graph.Nodes[i], startCorner, corner(i)) {
+					neighbours = append(neighbours, i)
+				}
+			}
+		} else {
+			neighbours = graph.Edges[current]
+			if reachesGoal[current] {
+				neighbours = append(neighbours[:len(neighbours):len(neighbours)], to)
+			}
+		}
+		for _, next := range neighbours {
+			if closed[next] {
+				continue
+			}
+			g := cost[current] + node(next).Clone().Sub(node(current)).Length()
+			if g >= cost[next] {
+				continue
+			}
+			cost[next] = g
+			came[next] = current
+			heap.Push(queue, priorityItem{g + node(next).Clone().Sub(goal).Length(), next})
+		}
+	}
+	if -1 == came[to] {
+		return nil
+	}
+
+	var path Path
+	for i := to; -1 != i; i = came[i] {
+		path = append(Path{node(i).Clone()}, path...)
+	}
+	return &path
+}
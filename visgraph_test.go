@@ -0,0 +1,70 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVisibilityGraphShortestPath(t *testing.T) {
+	box := NewRectangle(4, 4, 2, 2).ToPath()
+	graph := NewVisibilityGraph([]*Path{box}, 0)
+	if 4 != len(graph.Nodes) {
+		t.Fatal("box corners should be the graph nodes")
+	}
+	for _, edges := range graph.Edges {
+		if 2 != len(edges) {
+			t.Error("each corner should only see its 2 neighbours")
+		}
+	}
+
+	start, goal := NewVector(0, 5), NewVector(10, 5)
+	path := graph.ShortestPath(start, goal)
+	if nil == path || 4 != len(*path) {
+		t.Fatal("path should go around the box with 4 points")
+	}
+	if length := pathLength(path); math.Abs(length-(2*math.Sqrt(17)+2)) > 1e-9 {
+		t.Error("path should take the shortest route around the box")
+	}
+	if !graph.Visible(NewVector(0, 0), NewVector(10, 0)) || graph.Visible(start, goal) {
+		t.Error("only the line through the box should be blocked")
+	}
+
+	clear := graph.ShortestPath(NewVector(0, 0), NewVector(10, 0))
+	if nil == clear || 2 != len(*clear) {
+		t.Error("path below the box should be straight")
+	}
+	if nil != graph.ShortestPath(start, NewVector(5, 5)) {
+		t.Error("goal inside the box should not be reachable")
+	}
+}
+
+func TestVisibilityGraphThroughCorners(t *testing.T) {
+	graph := NewVisibilityGraph([]*Path{NewRectangle(0, 0, 2, 2).ToPath()}, 0)
+	path := graph.ShortestPath(NewVector(-1, -1), NewVector(9, 9))
+	if nil == path || 3 != len(*path) {
+		t.Fatal("path through opposite corners should go around the square")
+	}
+	if graph.Visible(NewVector(-1, -1), NewVector(3, 3)) {
+		t.Error("line through opposite corners should be blocked")
+	}
+	if !graph.Visible(NewVector(-1, 0), NewVector(3, 0)) {
+		t.Error("line along an edge should be visible")
+	}
+}
+
+func TestVisibilityGraphInflated(t *testing.T) {
+	wall := &Path{NewVector(5, -10), NewVector(6, -10), NewVector(6, 10), NewVector(5, 10)}
+	graph := NewVisibilityGraph([]*Path{wall}, 1)
+	path := graph.ShortestPath(NewVector(0, 0), NewVector(11, 0))
+	if nil == path {
+		t.Fatal("expected a path around the wall")
+	}
+	for _, point := range (*path)[1 : len(*path)-1] {
+		if math.Abs(point.Y) < 11-1e-9 {
+			t.Error("path corners should keep the radius away from the wall")
+		}
+	}
+	if nil != graph.ShortestPath(NewVector(4.5, 0), NewVector(11, 0)) {
+		t.Error("start within the radius of the wall should be blocked")
+	}
+}
@@ -0,0 +1,202 @@
+package geo2
+
+import (
+	"math"
+	"sort"
+)
+
+// visibilityEpsilon is the angle either side of each obstacle
+// corner at which extra rays are cast to look past it
+const visibilityEpsilon = 1e-5
+
+// VisibilityOptions limits the area which can be seen by a viewer
+type VisibilityOptions struct {
+	// Direction is the center of the view cone (ignored if
+	// Angle is zero or a full circle)
+	Direction *Vector
+	// Angle is the total width of the view cone in radians,
+	// with zero meaning that the viewer can see all around
+	Angle float64
+	// Radius is how far the viewer can see, with
+	// zero meaning only the bounds limit the view
+	Radius float64
+	// ArcSegments is how many segments a full circle is divided
+	// into when approximating the view radius (defaults to 32)
+	ArcSegments int
+}
+
+// VisibilityPolygon computes the region visible from the viewer when
+// looking past the given obstacles, limited to the given bounds. The
+// result is ordered counter clockwise by angle around the viewer, and
+// starts with the viewer itself when limited to a view cone. Returns nil
+// if the viewer lies outside of the bounds
+func VisibilityPolygon(viewer *Vector, obstacles []*Line, bounds *Rectangle, opts *VisibilityOptions) *Path {
+	if nil == bounds {
+		return nil
+	}
+	bounds = bounds.Clone().Normalize()
+	if !bounds.Contains(viewer) {
+		return nil
+	}
+	if nil == opts {
+		opts = &VisibilityOptions{}
+	}
+	arcs := opts.ArcSegments
+	if arcs <= 0 {
+		arcs = 32
+	}
+
+	segments := append(bounds.Edges(), obstacles...)
+	cone := nil != opts.Direction && opts.Direction.LengthSqd() > 0 &&
+		opts.Angle > 0 && opts.Angle < 2*math.Pi
+	base, half := 0.0, math.Pi
+	if cone {
+		base, half = math.Atan2(opts.Direction.Y, opts.Direction.X), opts.Angle/2
+	}
+
+	// angles are stored relative to the center of the view so
+	// that sorting them sweeps across the cone from one side
+	var angles []float64
+	add := func(angle float64) {
+		rel := wrapAngle(angle - base)
+		if cone && math.Abs(rel) > half {
+			return
+		}
+		angles = append(angles, rel)
+	}
+	addCorner := func(point *Vector) {
+		if point.Compare(viewer) {
+			return
+		}
+		angle := math.Atan2(point.Y-viewer.Y, point.X-viewer.X)
+		add(angle - visibilityEpsilon)
+		add(angle)
+		add(angle + visibilityEpsilon)
+	}
+	for _, seg := range segments {
+		addCorner(seg.A)
+		addCorner(seg.B)
+		if opts.Radius > 0 {
+			for _, point := range segmentCircle(seg.A, seg.B, viewer, opts.Radius) {
+				addCorner(point)
+			}
+		}
+	}
+	if opts.Radius > 0 {
+		for i := 0; i < arcs; i++ {
+			add(base + 2*math.Pi*float64(i)/float64(arcs))
+		}
+	}
+	if cone {
+		angles = append(angles, -half, half)
+	}
+	sort.Float64s(angles)
+
+	result := Path{}
+	if cone {
+		result = append(result, viewer.Clone())
+	}
+	for _, rel := range angles {
+		angle := base + rel
+		dir := NewVector(math.Cos(angle), math.Sin(angle))
+		best := math.Inf(1)
+		for _, seg := range segments {
+			if d, ok := raySegment(viewer, dir, seg.A, seg.B); ok && d < best {
+				best = d
+			}
+		}
+		if opts.Radius > 0 && best > opts.Radius {
+			best = opts.Radius
+		}
+		if math.IsInf(best, 1) {
+			continue
+		}
+		point := dir.MultiplyScalar(best).Add(viewer)
+		if len(result) > 0 && point.Clone().Sub(result[len(result)-1]).LengthSqd() < 1e-18 {
+			continue
+		}
+		result = append(result, point)
+	}
+	if !cone && len(result) > 1 && result[0].Clone().Sub(result[len(result)-1]).LengthSqd() < 1e-18 {
+		result = result[:len(result)-1]
+	}
+	return &result
+}
+
+// VisibilityPolygonPaths computes the region visible from the viewer
+// when looking past the given obstacles, each treated as a closed loop.
+// See VisibilityPolygon for details
+func VisibilityPolygonPaths(viewer *Vector, obstacles []*Path, bounds *Rectangle, opts *VisibilityOptions) *Path {
+	var edges []*Line
+	for _, path := range obstacles {
+		edges = append(edges, pathEdges(path, true)...)
+	}
+	return VisibilityPolygon(viewer, edges, bounds, opts)
+}
+
+// LineOfSight returns true if the straight line between the
+// two points does not cross or touch any of the given obstacles
+func LineOfSight(from, to *Vector, obstacles []*Line) bool {
+	for _, obstacle := range obstacles {
+		if segmentsCross(from, to, obstacle.A, obstacle.B) {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentsCross returns true if the segments ab and cd
+// intersect, including when they only touch
+func segmentsCross(a, b, c, d *Vector) bool {
+	d1 := orient(c, d, a)
+	d2 := orient(c, d, b)
+	d3 := orient(a, b, c)
+	d4 := orient(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (0 == d1 && onSegment(c, d, a)) || (0 == d2 && onSegment(c, d, b)) ||
+		(0 == d3 && onSegment(a, b, c)) || (0 == d4 && onSegment(a, b, d))
+}
+
+// onSegment returns true if the point, known to be collinear
+// with a and b, lies between them
+func onSegment(a, b, point *Vector) bool {
+	return point.X >= math.Min(a.X, b.X) && point.X <= math.Max(a.X, b.X) &&
+		point.Y >= math.Min(a.Y, b.Y) && point.Y <= math.Max(a.Y, b.Y)
+}
+
+// wrapAngle returns the given angle wrapped into [-π, π)
+func wrapAngle(angle float64) float64 {
+	angle = math.Mod(angle+math.Pi, 2*math.Pi)
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return angle - math.Pi
+}
+
+// segmentCircle returns the points where the
+// segment between a and b crosses the given circle
+func segmentCircle(a, b, center *Vector, radius float64) []*Vector {
+	d := b.Clone().Sub(a)
+	f := a.Clone().Sub(center)
+	qa := d.LengthSqd()
+	if 0 == qa {
+		return nil
+	}
+	qb := 2 * f.Dot(d)
+	qc := f.LengthSqd() - radius*radius
+	disc := qb*qb - 4*qa*qc
+	if disc < 0 {
+		return nil
+	}
+	root := math.Sqrt(disc)
+	var points []*Vector
+	for _, t := range []float64{(-qb - root) / (2 * qa), (-qb + root) / (2 * qa)} {
+		if t >= 0 && t <= 1 {
+			points = append(points, d.Clone().MultiplyScalar(t).Add(a))
+		}
+	}
+	return points
+}
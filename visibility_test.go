@@ -0,0 +1,57 @@
+package geo2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVisibilityPolygon(t *testing.T) {
+	bounds := NewRectangle(0, 0, 10, 10)
+	viewer := NewVector(5, 5)
+
+	open := VisibilityPolygon(viewer, nil, bounds, nil)
+	if nil == open || math.Abs(math.Abs(signedArea(*open))-100) > 1e-6 {
+		t.Fatal("whole bounds should be visible without obstacles")
+	}
+
+	wall := NewLine(NewVector(7, 3), NewVector(7, 7))
+	visible := VisibilityPolygon(viewer, []*Line{wall}, bounds, nil)
+	// the wall shadows a trapezoid from x=7 to x=10 reaching the corners
+	if area := signedArea(*visible); math.Abs(area-(100-3*(4+10)/2.0)) > 1e-3 {
+		t.Error("wall should shadow the area behind it")
+	}
+	if LineOfSight(viewer, NewVector(9, 5), []*Line{wall}) ||
+		!LineOfSight(viewer, NewVector(6, 9), []*Line{wall}) {
+		t.Error("line of sight should be blocked only by the wall")
+	}
+
+	if !LineOfSight(NewVector(5, 0), NewVector(5, 3), []*Line{NewLine(NewVector(0, 5), NewVector(10, 5))}) ||
+		!LineOfSight(NewVector(0, 2), NewVector(10, 2), []*Line{NewLine(NewVector(5, 6), NewVector(5, 10))}) {
+		t.Error("line of sight should not be blocked by walls it does not reach")
+	}
+	if LineOfSight(NewVector(5, 0), NewVector(5, 9), []*Line{NewLine(NewVector(0, 5), NewVector(10, 5))}) {
+		t.Error("vertical line of sight should be blocked by the wall")
+	}
+
+	if nil != VisibilityPolygon(NewVector(20, 20), nil, bounds, nil) {
+		t.Error("viewer outside of the bounds should see nothing")
+	}
+}
+
+func TestVisibilityCone(t *testing.T) {
+	bounds := NewRectangle(-100, -100, 200, 200)
+	opts := &VisibilityOptions{Direction: NewVector(1, 0), Angle: math.Pi / 2, Radius: 10, ArcSegments: 360}
+	cone := VisibilityPolygon(NewVector(0, 0), nil, bounds, opts)
+	if nil == cone || !(*cone)[0].Compare(NewVector(0, 0)) {
+		t.Fatal("cone should start at the viewer")
+	}
+	expected := math.Pi * 100 / 4
+	if area := signedArea(*cone); math.Abs(area-expected)/expected > 0.01 {
+		t.Error("cone should cover a quarter circle")
+	}
+	for _, point := range *cone {
+		if point.Length() > 10+1e-9 || point.X < -1e-9 {
+			t.Error("cone points should lie within the view radius and angle")
+		}
+	}
+}